@@ -0,0 +1,300 @@
+package camera
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// DefaultNALLengthSize 默认NAL单元长度前缀字节数
+const DefaultNALLengthSize = 4
+
+// 注册码流封装转换函数
+func init() {
+	RegisterConverter(FOURCC_H264, FOURCC_H264_NO_SC, convertCostCopy, func(src *Frame) (*Frame, error) {
+		data, err := AnnexBToLengthPrefixed(src.Data, DefaultNALLengthSize)
+		if err != nil {
+			return nil, err
+		}
+		return &Frame{Data: data, Config: src.Config}, nil
+	})
+	RegisterConverter(FOURCC_H264_NO_SC, FOURCC_H264, convertCostCopy, func(src *Frame) (*Frame, error) {
+		data, err := LengthPrefixedToAnnexB(src.Data, DefaultNALLengthSize)
+		if err != nil {
+			return nil, err
+		}
+		return &Frame{Data: data, Config: src.Config}, nil
+	})
+}
+
+// 检查长度前缀字节数
+func checkNALLengthSize(lengthSize int) error {
+	switch lengthSize {
+	case 1, 2, 4:
+		return nil
+	default:
+		return errors.Join(ErrInvalidParam, fmt.Errorf("invalid nal length size %d", lengthSize))
+	}
+}
+
+// 按长度前缀字节数写入NAL单元
+func appendLengthPrefixed(dst []byte, nal []byte, lengthSize int) ([]byte, error) {
+	if uint64(len(nal)) >= 1<<(8*uint(lengthSize)) {
+		return nil, errors.Join(ErrInvalidParam, fmt.Errorf("nal unit of %d bytes exceeds %d-byte length prefix", len(nal), lengthSize))
+	}
+	for i := lengthSize - 1; i >= 0; i-- {
+		dst = append(dst, byte(len(nal)>>(8*i)))
+	}
+	return append(dst, nal...), nil
+}
+
+// AnnexBToLengthPrefixed 将Annex-B字节流转换为长度前缀格式（AVC/HVCC，适用于MP4等容器）
+//
+//	@param	data		Annex-B字节流
+//	@param	lengthSize	长度前缀字节数（1、2或4）
+//	@return	长度前缀格式数据
+//	@return	异常信息
+func AnnexBToLengthPrefixed(data []byte, lengthSize int) ([]byte, error) {
+	if err := checkNALLengthSize(lengthSize); err != nil {
+		return nil, err
+	}
+	nals := SplitAnnexB(data)
+	if len(nals) == 0 {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("no nal unit found"))
+	}
+	res := make([]byte, 0, len(data)+len(nals)*lengthSize)
+	for _, nal := range nals {
+		var err error
+		if res, err = appendLengthPrefixed(res, nal, lengthSize); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// SplitLengthPrefixed 按长度前缀拆分NAL单元
+//
+//	@param	data		长度前缀格式数据
+//	@param	lengthSize	长度前缀字节数（1、2或4）
+//	@return	NAL单元列表（与原数据共享内存）
+//	@return	异常信息
+func SplitLengthPrefixed(data []byte, lengthSize int) ([][]byte, error) {
+	if err := checkNALLengthSize(lengthSize); err != nil {
+		return nil, err
+	}
+	var res [][]byte
+	for pos := 0; pos < len(data); {
+		if pos+lengthSize > len(data) {
+			return nil, errors.Join(ErrParseBitstreamFailed, fmt.Errorf("truncated nal length at offset %d", pos))
+		}
+		size := 0
+		for _, b := range data[pos : pos+lengthSize] {
+			size = size<<8 | int(b)
+		}
+		pos += lengthSize
+		if size == 0 || pos+size > len(data) {
+			return nil, errors.Join(ErrParseBitstreamFailed, fmt.Errorf("invalid nal length %d at offset %d", size, pos-lengthSize))
+		}
+		res = append(res, data[pos:pos+size])
+		pos += size
+	}
+	return res, nil
+}
+
+// LengthPrefixedToAnnexB 将长度前缀格式（AVC/HVCC）转换为Annex-B字节流（4字节起始码）
+//
+//	@param	data		长度前缀格式数据
+//	@param	lengthSize	长度前缀字节数（1、2或4）
+//	@return	Annex-B字节流
+//	@return	异常信息
+func LengthPrefixedToAnnexB(data []byte, lengthSize int) ([]byte, error) {
+	nals, err := SplitLengthPrefixed(data, lengthSize)
+	if err != nil {
+		return nil, err
+	}
+	return joinAnnexB(nals), nil
+}
+
+// 以4字节起始码连接NAL单元
+func joinAnnexB(nals [][]byte) []byte {
+	size := 0
+	for _, nal := range nals {
+		size += 4 + len(nal)
+	}
+	res := make([]byte, 0, size)
+	for _, nal := range nals {
+		res = append(res, 0x00, 0x00, 0x00, 0x01)
+		res = append(res, nal...)
+	}
+	return res
+}
+
+// 写入带2字节长度的参数集
+func appendParameterSet(dst []byte, nal []byte) ([]byte, error) {
+	if len(nal) > 0xffff {
+		return nil, errors.Join(ErrInvalidParam, fmt.Errorf("parameter set of %d bytes is too large", len(nal)))
+	}
+	dst = binary.BigEndian.AppendUint16(dst, uint16(len(nal)))
+	return append(dst, nal...), nil
+}
+
+// 读取带2字节长度的参数集
+func readParameterSet(data []byte, pos int) ([]byte, int, error) {
+	if pos+2 > len(data) {
+		return nil, 0, errors.New("truncated parameter set length")
+	}
+	size := int(binary.BigEndian.Uint16(data[pos:]))
+	if pos+2+size > len(data) {
+		return nil, 0, errors.New("truncated parameter set")
+	}
+	return data[pos+2 : pos+2+size], pos + 2 + size, nil
+}
+
+// AVCDecoderConfig H.264解码器配置记录（MP4中的avcC，ISO/IEC 14496-15）
+type AVCDecoderConfig struct {
+	ProfileIdc           uint8    // 档次
+	ProfileCompatibility uint8    // 档次兼容标志
+	LevelIdc             uint8    // 级别
+	LengthSize           int      // NAL单元长度前缀字节数
+	ChromaFormatIdc      uint8    // 色度格式（仅高档次写入）
+	BitDepthLuma         uint8    // 亮度位深（仅高档次写入）
+	BitDepthChroma       uint8    // 色度位深（仅高档次写入）
+	SPS                  [][]byte // 序列参数集（含1字节头）
+	PPS                  [][]byte // 图像参数集（含1字节头）
+}
+
+// 需要在avcC中写入色度格式与位深的档次
+var avcHighProfiles = map[uint8]bool{100: true, 110: true, 122: true, 144: true}
+
+// NewAVCDecoderConfig 根据参数集创建avcC
+//
+//	@param	sps	序列参数集列表（至少一个）
+//	@param	pps	图像参数集列表（至少一个）
+//	@return	解码器配置记录
+//	@return	异常信息
+func NewAVCDecoderConfig(sps, pps [][]byte) (*AVCDecoderConfig, error) {
+	if len(sps) == 0 || len(pps) == 0 || len(sps) > 31 || len(pps) > 255 {
+		return nil, errors.Join(ErrInvalidParam, fmt.Errorf("need 1~31 sps and 1~255 pps, got %d and %d", len(sps), len(pps)))
+	}
+	info, err := ParseH264SPS(sps[0])
+	if err != nil {
+		return nil, err
+	}
+	return &AVCDecoderConfig{
+		ProfileIdc:           info.ProfileIdc,
+		ProfileCompatibility: info.ConstraintFlags,
+		LevelIdc:             info.LevelIdc,
+		LengthSize:           DefaultNALLengthSize,
+		ChromaFormatIdc:      uint8(info.ChromaFormatIdc),
+		BitDepthLuma:         uint8(info.BitDepthLuma),
+		BitDepthChroma:       uint8(info.BitDepthChroma),
+		SPS:                  sps,
+		PPS:                  pps,
+	}, nil
+}
+
+// NewAVCDecoderConfigFromAnnexB 从携带参数集的Annex-B帧（通常为关键帧）创建avcC
+//
+//	@param	data	Annex-B帧数据
+//	@return	解码器配置记录
+//	@return	异常信息
+func NewAVCDecoderConfigFromAnnexB(data []byte) (*AVCDecoderConfig, error) {
+	var sps, pps [][]byte
+	for _, nal := range ParseH264NALUnits(data) {
+		switch nal.Type {
+		case H264NALSPS:
+			sps = append(sps, nal.Data)
+		case H264NALPPS:
+			pps = append(pps, nal.Data)
+		}
+	}
+	return NewAVCDecoderConfig(sps, pps)
+}
+
+// Marshal 序列化为avcC字节
+//
+//	@return	avcC数据
+//	@return	异常信息
+func (p *AVCDecoderConfig) Marshal() ([]byte, error) {
+	if err := checkNALLengthSize(p.LengthSize); err != nil {
+		return nil, err
+	}
+	if len(p.SPS) > 31 || len(p.PPS) > 255 {
+		return nil, errors.Join(ErrInvalidParam, errors.New("too many parameter sets"))
+	}
+	res := []byte{1, p.ProfileIdc, p.ProfileCompatibility, p.LevelIdc, 0xfc | byte(p.LengthSize-1), 0xe0 | byte(len(p.SPS))}
+	var err error
+	for _, nal := range p.SPS {
+		if res, err = appendParameterSet(res, nal); err != nil {
+			return nil, err
+		}
+	}
+	res = append(res, byte(len(p.PPS)))
+	for _, nal := range p.PPS {
+		if res, err = appendParameterSet(res, nal); err != nil {
+			return nil, err
+		}
+	}
+	if avcHighProfiles[p.ProfileIdc] {
+		res = append(res, 0xfc|p.ChromaFormatIdc&0x03, 0xf8|(p.BitDepthLuma-8)&0x07, 0xf8|(p.BitDepthChroma-8)&0x07, 0)
+	}
+	return res, nil
+}
+
+// ParseAVCDecoderConfig 解析avcC
+//
+//	@param	data	avcC数据
+//	@return	解码器配置记录
+//	@return	异常信息
+func ParseAVCDecoderConfig(data []byte) (*AVCDecoderConfig, error) {
+	if len(data) < 7 || data[0] != 1 {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("invalid avcC record"))
+	}
+	p := &AVCDecoderConfig{
+		ProfileIdc:           data[1],
+		ProfileCompatibility: data[2],
+		LevelIdc:             data[3],
+		LengthSize:           int(data[4]&0x03) + 1,
+		ChromaFormatIdc:      1,
+		BitDepthLuma:         8,
+		BitDepthChroma:       8,
+	}
+	if p.LengthSize == 3 {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("invalid avcC nal length size"))
+	}
+
+	// 参数集
+	pos := 6
+	for i := 0; i < int(data[5]&0x1f); i++ {
+		nal, next, err := readParameterSet(data, pos)
+		if err != nil {
+			return nil, errors.Join(ErrParseBitstreamFailed, err)
+		}
+		p.SPS, pos = append(p.SPS, nal), next
+	}
+	if pos >= len(data) {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("missing pps count"))
+	}
+	count := int(data[pos])
+	pos++
+	for i := 0; i < count; i++ {
+		nal, next, err := readParameterSet(data, pos)
+		if err != nil {
+			return nil, errors.Join(ErrParseBitstreamFailed, err)
+		}
+		p.PPS, pos = append(p.PPS, nal), next
+	}
+
+	// 高档次扩展（部分封装器会省略）
+	if avcHighProfiles[p.ProfileIdc] && pos+3 <= len(data) {
+		p.ChromaFormatIdc = data[pos] & 0x03
+		p.BitDepthLuma = data[pos+1]&0x07 + 8
+		p.BitDepthChroma = data[pos+2]&0x07 + 8
+	}
+	return p, nil
+}
+
+// AnnexBParameterSets 以Annex-B格式输出全部参数集（可放在关键帧之前供解码器初始化）
+func (p *AVCDecoderConfig) AnnexBParameterSets() []byte {
+	return joinAnnexB(append(append([][]byte(nil), p.SPS...), p.PPS...))
+}
@@ -0,0 +1,59 @@
+package camera
+
+// 常见格式的每像素位数（压缩格式为典型码率下的估算值）
+var fourccBitsPerPixel = map[Fourcc]float64{
+	// RGB
+	FOURCC_RGB332: 8, FOURCC_RGB444: 16, FOURCC_RGB555: 16, FOURCC_RGB565: 16, FOURCC_RGB555X: 16, FOURCC_RGB565X: 16,
+	FOURCC_BGR666: 18, FOURCC_BGR24: 24, FOURCC_RGB24: 24, FOURCC_HSV24: 24,
+	FOURCC_BGR32: 32, FOURCC_ABGR32: 32, FOURCC_XBGR32: 32, FOURCC_BGRA32: 32, FOURCC_BGRX32: 32,
+	FOURCC_RGB32: 32, FOURCC_RGBA32: 32, FOURCC_RGBX32: 32, FOURCC_ARGB32: 32, FOURCC_XRGB32: 32, FOURCC_HSV32: 32,
+
+	// 灰度与深度
+	FOURCC_GREY: 8, FOURCC_Y4: 4, FOURCC_Y6: 6, FOURCC_Y10: 16, FOURCC_Y12: 16, FOURCC_Y14: 16, FOURCC_Y16: 16,
+	FOURCC_Y10BPACK: 10, FOURCC_Y10P: 10, FOURCC_Y8I: 16, FOURCC_Y12I: 24, FOURCC_Z16: 16, FOURCC_INZI: 26, FOURCC_CNF4: 4,
+
+	// YUV打包格式
+	FOURCC_YUYV: 16, FOURCC_YUY2: 16, FOURCC_YYUV: 16, FOURCC_YVYU: 16, FOURCC_YVY2: 16, FOURCC_UYVY: 16, FOURCC_VYUY: 16,
+	FOURCC_Y41P: 12, FOURCC_YUV444: 16, FOURCC_YUV555: 16, FOURCC_YUV565: 16, FOURCC_YUV24: 24,
+	FOURCC_YUV32: 32, FOURCC_AYUV32: 32, FOURCC_XYUV32: 32, FOURCC_VUYA32: 32, FOURCC_VUYX32: 32, FOURCC_M420: 12,
+
+	// YUV平面格式
+	FOURCC_NV12: 12, FOURCC_NV21: 12, FOURCC_NV16: 16, FOURCC_NV61: 16, FOURCC_NV24: 24, FOURCC_NV42: 24,
+	FOURCC_NV12M: 12, FOURCC_NV21M: 12, FOURCC_NV16M: 16, FOURCC_NV61M: 16,
+	FOURCC_YUV410: 9, FOURCC_YVU410: 9, FOURCC_YUV411P: 12, FOURCC_YUV420: 12, FOURCC_YVU420: 12, FOURCC_YUV422P: 16,
+	FOURCC_YUV420M: 12, FOURCC_YVU420M: 12, FOURCC_YUV422M: 16, FOURCC_YVU422M: 16, FOURCC_YUV444M: 24, FOURCC_YVU444M: 24,
+
+	// Bayer
+	FOURCC_SBGGR8: 8, FOURCC_SGBRG8: 8, FOURCC_SGRBG8: 8, FOURCC_SRGGB8: 8,
+	FOURCC_SBGGR10: 16, FOURCC_SGBRG10: 16, FOURCC_SGRBG10: 16, FOURCC_SRGGB10: 16,
+	FOURCC_SBGGR10P: 10, FOURCC_SGBRG10P: 10, FOURCC_SGRBG10P: 10, FOURCC_SRGGB10P: 10,
+	FOURCC_SBGGR12: 16, FOURCC_SGBRG12: 16, FOURCC_SGRBG12: 16, FOURCC_SRGGB12: 16,
+	FOURCC_SBGGR12P: 12, FOURCC_SGBRG12P: 12, FOURCC_SGRBG12P: 12, FOURCC_SRGGB12P: 12,
+	FOURCC_SBGGR16: 16, FOURCC_SGBRG16: 16, FOURCC_SGRBG16: 16, FOURCC_SRGGB16: 16,
+
+	// 压缩格式
+	FOURCC_MJPEG: 3, FOURCC_JPEG: 3, FOURCC_H264: 0.2, FOURCC_H264_NO_SC: 0.2, FOURCC_HEVC: 0.12,
+	FOURCC_VP8: 0.2, FOURCC_VP9: 0.12, FOURCC_MPEG4: 0.3, FOURCC_MPEG2: 0.4,
+}
+
+// 未知格式按YUV 4:2:2估算
+const defaultBitsPerPixel = 16
+
+// FourccBitsPerPixel 格式的每像素位数（压缩格式返回典型码率下的估算值，未知格式按16位估算）
+//
+//	@param	format	帧格式
+//	@return	每像素位数
+func FourccBitsPerPixel(format Fourcc) float64 {
+	if bpp, ok := fourccBitsPerPixel[format]; ok {
+		return bpp
+	}
+	return defaultBitsPerPixel
+}
+
+// EstimateBandwidth 估算配置的数据带宽
+//
+//	@param	cfg	配置信息
+//	@return	每秒字节数
+func EstimateBandwidth(cfg DeviceConfig) float64 {
+	return float64(cfg.Width) * float64(cfg.Height) * FourccBitsPerPixel(cfg.Format) / 8 * cfg.FrameRate()
+}
@@ -0,0 +1,210 @@
+package camera
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// USB2IsochronousBandwidth USB 2.0高速等时传输的最大带宽（每微帧3×1024字节，单位：字节/秒）
+const USB2IsochronousBandwidth = 3 * 1024 * 8000
+
+// 常见宽高比（用于归并无法整除的分辨率，如1366x768）
+var commonAspectRatios = [][2]uint32{{1, 1}, {5, 4}, {4, 3}, {3, 2}, {16, 10}, {16, 9}, {21, 9}}
+
+// AspectRatio 计算分辨率的宽高比（约分后过于零碎时归并到相近的常见宽高比）
+//
+//	@param	width	宽度
+//	@param	height	高度
+//	@return	宽高比（如"16:9"，宽高为0时返回空字符串）
+func AspectRatio(width, height uint32) string {
+	if width == 0 || height == 0 {
+		return ""
+	}
+	a, b := width, height
+	for b != 0 {
+		a, b = b, a%b
+	}
+	w, h := width/a, height/a
+	if w > 32 || h > 32 {
+		ratio := float64(width) / float64(height)
+		for _, v := range commonAspectRatios {
+			if math.Abs(ratio/(float64(v[0])/float64(v[1]))-1) < 0.015 {
+				return fmt.Sprintf("%d:%d", v[0], v[1])
+			}
+		}
+	}
+	return fmt.Sprintf("%d:%d", w, h)
+}
+
+// FrameRateCapability 帧率能力
+type FrameRateCapability struct {
+	Interval  FrameInterval // 帧间隔
+	Bandwidth float64       // 估算的数据带宽（字节/秒）
+}
+
+// ExceedsUSB2 估算带宽是否超过USB 2.0等时传输上限（超过时通常会出现卡顿或丢帧）
+func (p FrameRateCapability) ExceedsUSB2() bool {
+	return p.Bandwidth > USB2IsochronousBandwidth
+}
+
+// ResolutionCapability 分辨率能力
+type ResolutionCapability struct {
+	Width      uint32                // 宽度
+	Height     uint32                // 高度
+	Aspect     string                // 宽高比
+	FrameRates []FrameRateCapability // 支持的帧率（从高到低）
+}
+
+// FormatCapability 格式能力
+type FormatCapability struct {
+	Format       Fourcc                  // 帧格式
+	BitsPerPixel float64                 // 每像素位数（压缩格式为估算值）
+	Resolutions  []*ResolutionCapability // 支持的分辨率（按像素数从大到小）
+}
+
+// MaxResolution 格式支持的最大分辨率（按像素数比较）
+//
+//	@return	宽度
+//	@return	高度
+func (p *FormatCapability) MaxResolution() (uint32, uint32) {
+	if len(p.Resolutions) == 0 {
+		return 0, 0
+	}
+	return p.Resolutions[0].Width, p.Resolutions[0].Height
+}
+
+// DeviceCapabilities 设备能力树（格式 → 分辨率 → 帧率）
+type DeviceCapabilities struct {
+	Formats []*FormatCapability // 支持的格式（保持配置列表中的出现顺序）
+}
+
+// Format 获取指定格式的能力
+//
+//	@param	format	帧格式
+//	@return	格式能力（不支持时为nil）
+func (p *DeviceCapabilities) Format(format Fourcc) *FormatCapability {
+	for _, v := range p.Formats {
+		if v.Format == format {
+			return v
+		}
+	}
+	return nil
+}
+
+// AspectRatios 所有格式支持的宽高比（按出现次数从多到少）
+func (p *DeviceCapabilities) AspectRatios() []string {
+	count := map[string]int{}
+	var res []string
+	for _, f := range p.Formats {
+		for _, r := range f.Resolutions {
+			if count[r.Aspect] == 0 {
+				res = append(res, r.Aspect)
+			}
+			count[r.Aspect]++
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return count[res[i]] > count[res[j]] })
+	return res
+}
+
+// String 以缩进文本输出能力树（超过USB 2.0带宽的模式会被标注）
+func (p *DeviceCapabilities) String() string {
+	var b strings.Builder
+	for _, f := range p.Formats {
+		w, h := f.MaxResolution()
+		fmt.Fprintf(&b, "%s (max %dx%d, %g bpp)\n", f.Format, w, h, f.BitsPerPixel)
+		for _, r := range f.Resolutions {
+			fmt.Fprintf(&b, "  %dx%d (%s)\n", r.Width, r.Height, r.Aspect)
+			for _, v := range r.FrameRates {
+				fmt.Fprintf(&b, "    %s fps %.1f MB/s", v.Interval.RateString(), v.Bandwidth/1e6)
+				if v.ExceedsUSB2() {
+					b.WriteString(" (exceeds USB 2.0)")
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// Capabilities 将配置列表整理为能力树
+//
+//	@return	设备能力树
+func (s DeviceConfigList) Capabilities() *DeviceCapabilities {
+	res := &DeviceCapabilities{}
+	for _, v := range s {
+		if v == nil {
+			continue
+		}
+
+		// 格式
+		format := res.Format(v.Format)
+		if format == nil {
+			format = &FormatCapability{Format: v.Format, BitsPerPixel: FourccBitsPerPixel(v.Format)}
+			res.Formats = append(res.Formats, format)
+		}
+
+		// 分辨率
+		var resolution *ResolutionCapability
+		for _, r := range format.Resolutions {
+			if r.Width == v.Width && r.Height == v.Height {
+				resolution = r
+				break
+			}
+		}
+		if resolution == nil {
+			resolution = &ResolutionCapability{Width: v.Width, Height: v.Height, Aspect: AspectRatio(v.Width, v.Height)}
+			format.Resolutions = append(format.Resolutions, resolution)
+		}
+
+		// 帧率（去重）
+		exists := false
+		for _, r := range resolution.FrameRates {
+			if r.Interval.Cmp(v.FrameInterval()) == 0 {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			resolution.FrameRates = append(resolution.FrameRates, FrameRateCapability{Interval: v.FrameInterval(), Bandwidth: EstimateBandwidth(*v)})
+		}
+	}
+
+	// 排序
+	for _, f := range res.Formats {
+		sort.SliceStable(f.Resolutions, func(i, j int) bool {
+			a, b := f.Resolutions[i], f.Resolutions[j]
+			if pa, pb := uint64(a.Width)*uint64(a.Height), uint64(b.Width)*uint64(b.Height); pa != pb {
+				return pa > pb
+			}
+			return a.Width > b.Width
+		})
+		for _, r := range f.Resolutions {
+			rates := r.FrameRates
+			sort.SliceStable(rates, func(i, j int) bool { return rates[i].Interval.Cmp(rates[j].Interval) > 0 })
+		}
+	}
+	return res
+}
+
+// MaxResolution 获取指定格式支持的最大分辨率
+//
+//	@param	format	帧格式
+//	@return	宽度
+//	@return	高度
+//	@return	异常信息
+func (s DeviceConfigList) MaxResolution(format Fourcc) (uint32, uint32, error) {
+	f := s.Capabilities().Format(format)
+	if f == nil {
+		return 0, 0, ErrDeviceMediaConfigNotFound
+	}
+	w, h := f.MaxResolution()
+	return w, h, nil
+}
+
+// AspectRatios 获取列表中支持的宽高比（按出现次数从多到少）
+func (s DeviceConfigList) AspectRatios() []string {
+	return s.Capabilities().AspectRatios()
+}
@@ -0,0 +1,260 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 宽高比筛选的允许误差
+const configQueryAspectTolerance = 0.01
+
+// ConfigQuery 采集需求查询条件
+//
+// 查询语句由空格分隔的条件组成，例如“1280x720@30 MJPG|YUYV aspect=16:9 minfps=25”或“max-res NV12”：
+//
+//	1280x720@30		期望分辨率与帧率（也可单独写“1280x720”或“@30”，帧率可写作“29.97”或“30000/1001”），按评分选择最接近的配置
+//	MJPG|YUYV		允许的格式（按偏好顺序，不足4字符的格式名以空格补齐，如“Y16”）
+//	aspect=16:9		宽高比（也可写为小数，如“aspect=1.78”）
+//	minfps=25		最低帧率
+//	maxfps=60		最高帧率
+//	minres=640x480	最低分辨率
+//	maxres=1920x1080	最高分辨率
+//	max-res			选择最高分辨率
+//	max-fps			选择最高帧率
+type ConfigQuery struct {
+	Width   uint32        // 期望宽度
+	Height  uint32        // 期望高度
+	FPS     FrameInterval // 期望帧率
+	Formats []Fourcc      // 允许的格式（按偏好顺序）
+	Aspect  float64       // 宽高比（为0时不限制）
+	MinFPS  FrameInterval // 最低帧率
+	MaxFPS  FrameInterval // 最高帧率（为零值时不限制）
+	MinRes  [2]uint32     // 最低分辨率（宽、高）
+	MaxRes  [2]uint32     // 最高分辨率（宽、高，为0时不限制）
+	BestRes bool          // 选择最高分辨率
+	BestFPS bool          // 选择最高帧率
+
+	aspectText string // 原始宽高比写法（用于String）
+}
+
+// 解析分辨率（如“1280x720”）
+func parseQueryResolution(s string) (w, h uint32, err error) {
+	ws, hs, ok := strings.Cut(strings.ToLower(s), "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid resolution %q", s)
+	}
+	wv, err1 := strconv.ParseUint(ws, 10, 32)
+	hv, err2 := strconv.ParseUint(hs, 10, 32)
+	if err1 != nil || err2 != nil || wv == 0 || hv == 0 {
+		return 0, 0, fmt.Errorf("invalid resolution %q", s)
+	}
+	return uint32(wv), uint32(hv), nil
+}
+
+// 解析宽高比（如“16:9”或“1.78”）
+func parseQueryAspect(s string) (float64, error) {
+	if ws, hs, ok := strings.Cut(s, ":"); ok {
+		w, err1 := strconv.ParseFloat(ws, 64)
+		h, err2 := strconv.ParseFloat(hs, 64)
+		if err1 != nil || err2 != nil || w <= 0 || h <= 0 {
+			return 0, fmt.Errorf("invalid aspect ratio %q", s)
+		}
+		return w / h, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("invalid aspect ratio %q", s)
+	}
+	return v, nil
+}
+
+// 解析格式列表（如“MJPG|YUYV”）
+func parseQueryFormats(s string) ([]Fourcc, error) {
+	var res []Fourcc
+	for _, name := range strings.Split(s, "|") {
+		if len(name) == 0 || len(name) > 4 {
+			return nil, fmt.Errorf("invalid format %q", name)
+		}
+		res = append(res, Fourcc(name+strings.Repeat(" ", 4-len(name))))
+	}
+	return res, nil
+}
+
+// ParseConfigQuery 解析采集需求查询语句
+//
+//	@param	query	查询语句
+//	@return	查询条件
+//	@return	异常信息
+func ParseConfigQuery(query string) (*ConfigQuery, error) {
+	res := &ConfigQuery{}
+	for _, token := range strings.Fields(query) {
+		var err error
+		key, value, isOption := strings.Cut(token, "=")
+		switch {
+		case isOption:
+			switch strings.ToLower(key) {
+			case "aspect":
+				res.Aspect, err = parseQueryAspect(value)
+				res.aspectText = value
+			case "minfps":
+				res.MinFPS, err = ParseFrameRate(value)
+			case "maxfps":
+				res.MaxFPS, err = ParseFrameRate(value)
+			case "minres":
+				res.MinRes[0], res.MinRes[1], err = parseQueryResolution(value)
+			case "maxres":
+				res.MaxRes[0], res.MaxRes[1], err = parseQueryResolution(value)
+			default:
+				err = fmt.Errorf("unknown option %q", key)
+			}
+		case strings.EqualFold(token, "max-res"):
+			res.BestRes = true
+		case strings.EqualFold(token, "max-fps"):
+			res.BestFPS = true
+		case strings.HasPrefix(token, "@"):
+			res.FPS, err = ParseFrameRate(token[1:])
+		case token[0] >= '0' && token[0] <= '9' && strings.ContainsAny(token, "xX"):
+			// 分辨率与可选的帧率
+			resolution, fps, hasFPS := strings.Cut(token, "@")
+			if res.Width, res.Height, err = parseQueryResolution(resolution); err == nil && hasFPS {
+				res.FPS, err = ParseFrameRate(fps)
+			}
+		default:
+			var formats []Fourcc
+			if formats, err = parseQueryFormats(token); err == nil {
+				res.Formats = append(res.Formats, formats...)
+			}
+		}
+		if err != nil && !errors.Is(err, ErrInvalidParam) {
+			err = errors.Join(ErrInvalidParam, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// String 转换为查询语句
+func (p *ConfigQuery) String() string {
+	var tokens []string
+	if p.Width > 0 && p.Height > 0 {
+		token := fmt.Sprintf("%dx%d", p.Width, p.Height)
+		if !p.FPS.IsZero() {
+			token += "@" + p.FPS.RateString()
+		}
+		tokens = append(tokens, token)
+	} else if !p.FPS.IsZero() {
+		tokens = append(tokens, "@"+p.FPS.RateString())
+	}
+	if len(p.Formats) > 0 {
+		names := make([]string, len(p.Formats))
+		for i, v := range p.Formats {
+			names[i] = strings.TrimRight(string(v), " ")
+		}
+		tokens = append(tokens, strings.Join(names, "|"))
+	}
+	if p.Aspect > 0 {
+		// 优先保留原始写法（如“16:9”）
+		aspect := p.aspectText
+		if v, err := parseQueryAspect(aspect); err != nil || v != p.Aspect {
+			aspect = strconv.FormatFloat(p.Aspect, 'f', -1, 64)
+		}
+		tokens = append(tokens, "aspect="+aspect)
+	}
+	if !p.MinFPS.IsZero() {
+		tokens = append(tokens, "minfps="+p.MinFPS.RateString())
+	}
+	if !p.MaxFPS.IsZero() {
+		tokens = append(tokens, "maxfps="+p.MaxFPS.RateString())
+	}
+	if p.MinRes[0] > 0 {
+		tokens = append(tokens, fmt.Sprintf("minres=%dx%d", p.MinRes[0], p.MinRes[1]))
+	}
+	if p.MaxRes[0] > 0 {
+		tokens = append(tokens, fmt.Sprintf("maxres=%dx%d", p.MaxRes[0], p.MaxRes[1]))
+	}
+	if p.BestRes {
+		tokens = append(tokens, "max-res")
+	}
+	if p.BestFPS {
+		tokens = append(tokens, "max-fps")
+	}
+	return strings.Join(tokens, " ")
+}
+
+// 配置是否满足硬性条件
+func (p *ConfigQuery) match(cfg *DeviceConfig) bool {
+	if p.Aspect > 0 && (cfg.Height == 0 || math.Abs(float64(cfg.Width)/float64(cfg.Height)/p.Aspect-1) > configQueryAspectTolerance) {
+		return false
+	}
+	if interval := cfg.FrameInterval(); interval.Cmp(p.MinFPS) < 0 || (!p.MaxFPS.IsZero() && interval.Cmp(p.MaxFPS) > 0) {
+		return false
+	}
+	if cfg.Width < p.MinRes[0] || cfg.Height < p.MinRes[1] {
+		return false
+	}
+	if p.MaxRes[0] > 0 && (cfg.Width > p.MaxRes[0] || cfg.Height > p.MaxRes[1]) {
+		return false
+	}
+	return true
+}
+
+// Rank 按查询条件对配置排序（不满足硬性条件的配置会被排除）
+//
+//	@param	list	配置信息列表
+//	@return	排序后的候选配置（最匹配的在前）
+func (p *ConfigQuery) Rank(list DeviceConfigList) []*ConfigCandidate {
+	// 筛选
+	var matched DeviceConfigList
+	for _, v := range list {
+		if v != nil && p.match(v) {
+			matched = append(matched, v)
+		}
+	}
+
+	// 评分排序
+	res := matched.Rank(&ConfigSelector{Width: p.Width, Height: p.Height, FPS: p.FPS.Rate(), Formats: p.Formats})
+	if p.BestRes {
+		sort.SliceStable(res, func(i, j int) bool {
+			a, b := res[i].Config, res[j].Config
+			return uint64(a.Width)*uint64(a.Height) > uint64(b.Width)*uint64(b.Height)
+		})
+	}
+	if p.BestFPS {
+		sort.SliceStable(res, func(i, j int) bool {
+			return res[i].Config.FrameInterval().Cmp(res[j].Config.FrameInterval()) > 0
+		})
+	}
+	return res
+}
+
+// Resolve 获取最满足查询条件的配置
+//
+//	@param	list	配置信息列表
+//	@return	配置信息
+//	@return	异常信息
+func (p *ConfigQuery) Resolve(list DeviceConfigList) (*DeviceConfig, error) {
+	res := p.Rank(list)
+	if len(res) == 0 {
+		return nil, errors.Join(ErrDeviceMediaConfigNotFound, fmt.Errorf("no config matches %q", p.String()))
+	}
+	return res[0].Config, nil
+}
+
+// Query 通过查询语句获取配置（如“1280x720@30 MJPG|YUYV aspect=16:9 minfps=25”，语法见ConfigQuery）
+//
+//	@param	query	查询语句
+//	@return	配置信息
+//	@return	异常信息
+func (s DeviceConfigList) Query(query string) (*DeviceConfig, error) {
+	q, err := ParseConfigQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return q.Resolve(s)
+}
@@ -0,0 +1,179 @@
+package camera
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConfigWeights 配置评分权重（各项惩罚分乘以权重后累加，总分越低越匹配）
+type ConfigWeights struct {
+	Resolution float64 // 分辨率距离（按像素数的对数距离计算，低于期望分辨率时加倍）
+	Aspect     float64 // 宽高比偏差
+	FPS        float64 // 帧率不足（高于期望帧率不扣分）
+	Format     float64 // 格式偏好顺序
+	Bandwidth  float64 // 数据带宽（相对于候选配置中的最大带宽）
+}
+
+// DefaultConfigWeights 默认配置评分权重
+var DefaultConfigWeights = ConfigWeights{
+	Resolution: 1,
+	Aspect:     2,
+	FPS:        2,
+	Format:     1,
+	Bandwidth:  0.2,
+}
+
+// ConfigSelector 基于评分的配置选择器
+type ConfigSelector struct {
+	Width   uint32        // 期望宽度（宽高为0时不关心分辨率）
+	Height  uint32        // 期望高度
+	FPS     float64       // 期望最低帧率（可为小数，如29.97；为0时不关心帧率）
+	Formats []Fourcc      // 格式偏好顺序（越靠前越优先，未列出的格式不参与选择；为空时不限制格式）
+	Weights ConfigWeights // 评分权重（零值时使用DefaultConfigWeights）
+}
+
+// ConfigScoreItem 单项评分
+type ConfigScoreItem struct {
+	Name    string  // 评分项名称
+	Penalty float64 // 加权后的惩罚分
+	Detail  string  // 评分说明
+}
+
+// ConfigCandidate 候选配置
+type ConfigCandidate struct {
+	Config *DeviceConfig     // 配置信息
+	Score  float64           // 总惩罚分（越低越匹配）
+	Items  []ConfigScoreItem // 各项评分明细
+}
+
+// Explain 候选配置的评分说明
+func (p *ConfigCandidate) Explain() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s score %.3f", p.Config, p.Score)
+	for i, item := range p.Items {
+		sep := "; "
+		if i == 0 {
+			sep = ": "
+		}
+		fmt.Fprintf(&b, "%s%s %s (+%.3f)", sep, item.Name, item.Detail, item.Penalty)
+	}
+	return b.String()
+}
+
+// 格式偏好序号（-1表示不在偏好列表中）
+func (p *ConfigSelector) formatIndex(format Fourcc) int {
+	if len(p.Formats) == 0 {
+		return 0
+	}
+	for i, v := range p.Formats {
+		if v == format {
+			return i
+		}
+	}
+	return -1
+}
+
+// 对单个配置评分
+//
+//	@param	cfg				配置信息
+//	@param	maxBandwidth	候选配置中的最大带宽
+//	@return	候选配置
+func (p *ConfigSelector) score(cfg *DeviceConfig, maxBandwidth float64) *ConfigCandidate {
+	weights := p.Weights
+	if weights == (ConfigWeights{}) {
+		weights = DefaultConfigWeights
+	}
+	res := &ConfigCandidate{Config: cfg.Clone()}
+	add := func(name string, weight, penalty float64, detail string) {
+		res.Items = append(res.Items, ConfigScoreItem{Name: name, Penalty: weight * penalty, Detail: detail})
+		res.Score += weight * penalty
+	}
+
+	// 分辨率与宽高比
+	if p.Width > 0 && p.Height > 0 && cfg.Width > 0 && cfg.Height > 0 {
+		ratio := float64(cfg.Width) * float64(cfg.Height) / (float64(p.Width) * float64(p.Height))
+		distance := math.Abs(math.Log2(ratio))
+		if ratio < 1 {
+			distance *= 2
+		}
+		add("resolution", weights.Resolution, distance, fmt.Sprintf("%dx%d vs %dx%d (%.2fx pixels)", cfg.Width, cfg.Height, p.Width, p.Height, ratio))
+
+		aspect := float64(cfg.Width) / float64(cfg.Height)
+		want := float64(p.Width) / float64(p.Height)
+		add("aspect", weights.Aspect, math.Abs(math.Log2(aspect/want)), fmt.Sprintf("%.3f vs %.3f", aspect, want))
+	}
+
+	// 帧率不足
+	if p.FPS > 0 {
+		shortfall := 0.0
+		if rate := cfg.FrameRate(); rate < p.FPS {
+			shortfall = (p.FPS - rate) / p.FPS
+		}
+		add("fps", weights.FPS, shortfall, fmt.Sprintf("%s vs %s", cfg.FrameInterval().RateString(), strconv.FormatFloat(p.FPS, 'f', -1, 64)))
+	}
+
+	// 格式偏好
+	if len(p.Formats) > 1 {
+		index := p.formatIndex(cfg.Format)
+		add("format", weights.Format, float64(index)/float64(len(p.Formats)), fmt.Sprintf("%s is preference %d of %d", cfg.Format, index+1, len(p.Formats)))
+	}
+
+	// 数据带宽
+	if maxBandwidth > 0 {
+		bandwidth := EstimateBandwidth(*cfg)
+		add("bandwidth", weights.Bandwidth, bandwidth/maxBandwidth, fmt.Sprintf("%.1f MB/s", bandwidth/1e6))
+	}
+	return res
+}
+
+// Rank 对列表中的配置评分并按匹配程度排序（格式不在偏好列表中的配置会被排除）
+//
+//	@param	selector	配置选择器
+//	@return	排序后的候选配置（最匹配的在前）
+func (s DeviceConfigList) Rank(selector *ConfigSelector) []*ConfigCandidate {
+	// 筛选格式
+	var list DeviceConfigList
+	maxBandwidth := 0.0
+	for _, v := range s {
+		if v == nil || selector.formatIndex(v.Format) < 0 {
+			continue
+		}
+		list = append(list, v)
+		maxBandwidth = math.Max(maxBandwidth, EstimateBandwidth(*v))
+	}
+
+	// 评分
+	res := make([]*ConfigCandidate, 0, len(list))
+	for _, v := range list {
+		res = append(res, selector.score(v, maxBandwidth))
+	}
+
+	// 按总分从低到高排序，同分时优先高帧率、高分辨率
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.Score != b.Score {
+			return a.Score < b.Score
+		}
+		if c := a.Config.FrameInterval().Cmp(b.Config.FrameInterval()); c != 0 {
+			return c > 0
+		}
+		return uint64(a.Config.Width)*uint64(a.Config.Height) > uint64(b.Config.Width)*uint64(b.Config.Height)
+	})
+	return res
+}
+
+// GetBest 获取评分最高的配置
+//
+//	@param	selector	配置选择器
+//	@return	最匹配的候选配置
+//	@return	异常信息
+func (s DeviceConfigList) GetBest(selector *ConfigSelector) (*ConfigCandidate, error) {
+	res := s.Rank(selector)
+	if len(res) == 0 {
+		return nil, ErrDeviceMediaConfigNotFound
+	}
+	return res[0], nil
+}
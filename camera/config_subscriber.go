@@ -0,0 +1,81 @@
+package camera
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ConfigChange 配置变更通知
+type ConfigChange struct {
+	Device Device        // 相机信息
+	Old    DeviceConfig  // 变更前的配置
+	New    DeviceConfig  // 变更后的配置
+	Cost   time.Duration // 切换耗时
+}
+
+// ConfigSubscribers 配置变更订阅者集合（并发安全）
+type ConfigSubscribers struct {
+	mutex sync.Mutex
+	next  int
+	subs  map[int]func(ConfigChange)
+}
+
+// Subscribe 订阅配置变更
+//
+//	@param	fn	回调函数（在变更完成后同步调用，可在回调中调用Manager的方法）
+//	@return	取消订阅函数（可重复调用）
+func (p *ConfigSubscribers) Subscribe(fn func(ConfigChange)) func() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.subs == nil {
+		p.subs = make(map[int]func(ConfigChange))
+	}
+	id := p.next
+	p.next++
+	p.subs[id] = fn
+	return func() {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		delete(p.subs, id)
+	}
+}
+
+// Len 订阅者数量
+func (p *ConfigSubscribers) Len() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return len(p.subs)
+}
+
+// Notify 按订阅顺序通知所有订阅者
+//
+//	@param	change	配置变更
+func (p *ConfigSubscribers) Notify(change ConfigChange) {
+	// 拷贝订阅者，避免回调中订阅或取消订阅时死锁
+	p.mutex.Lock()
+	ids := make([]int, 0, len(p.subs))
+	for id := range p.subs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	fns := make([]func(ConfigChange), 0, len(ids))
+	for _, id := range ids {
+		fns = append(fns, p.subs[id])
+	}
+	p.mutex.Unlock()
+
+	for _, fn := range fns {
+		fn(change)
+	}
+}
+
+// FrameStats 取帧统计（重新打开相机时清零，Reconfigure时保留）
+type FrameStats struct {
+	Frames          uint64        // 成功获取的帧数
+	CorruptedFrames uint64        // 损坏帧数
+	Bytes           uint64        // 成功获取的字节数
+	Reconfigures    uint64        // 在线切换配置次数
+	LastReconfigure time.Duration // 最近一次切换配置的耗时
+}
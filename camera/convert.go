@@ -0,0 +1,167 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ConvertFunc 帧格式转换函数
+//
+//	@param	src	源帧
+//	@return	目标帧（格式由转换图在帧信息的副本上设置，返回源帧本身时帧数据与源帧共享）
+//	@return	异常信息
+type ConvertFunc func(src *Frame) (*Frame, error)
+
+// 转换图中的一条边
+type convertEdge struct {
+	to   Fourcc      // 目标格式
+	cost int         // 转换代价
+	fn   ConvertFunc // 转换函数
+}
+
+// 格式转换图
+var (
+	convertMutex sync.RWMutex                     // 读写锁
+	convertGraph = make(map[Fourcc][]convertEdge) // 按源格式索引的转换边
+)
+
+// RegisterConverter 注册帧格式转换函数（同一对格式重复注册时覆盖已有的转换函数）
+//
+//	@param	from	源格式
+//	@param	to		目标格式
+//	@param	cost	转换代价（用于在多条路径中选择代价最小者，通常按每像素耗时估算）
+//	@param	fn		转换函数
+func RegisterConverter(from, to Fourcc, cost int, fn ConvertFunc) {
+	if fn == nil || from == to {
+		return
+	}
+	if cost < 1 {
+		cost = 1
+	}
+
+	// 操作加锁
+	convertMutex.Lock()
+	defer convertMutex.Unlock()
+
+	// 覆盖已有的转换边
+	edges := convertGraph[from]
+	for i := range edges {
+		if edges[i].to == to {
+			edges[i] = convertEdge{to: to, cost: cost, fn: fn}
+			return
+		}
+	}
+	convertGraph[from] = append(edges, convertEdge{to: to, cost: cost, fn: fn})
+}
+
+// 查找代价最小的转换路径（无锁）
+//
+//	@param	from	源格式
+//	@param	to		目标格式
+//	@return	转换边路径（不含源格式）
+//	@return	是否找到
+func findConvertPath(from, to Fourcc) ([]convertEdge, bool) {
+	// Dijkstra，格式数量很少，直接线性查找最小值（代价相同时按格式排序以保证结果稳定）
+	type node struct {
+		cost int         // 累计代价
+		prev Fourcc      // 前驱格式
+		edge convertEdge // 到达该格式的边
+		done bool        // 是否已确定
+	}
+	nodes := map[Fourcc]*node{from: {}}
+	for {
+		// 取出未确定的最小代价节点
+		var cur Fourcc
+		var curNode *node
+		for k, v := range nodes {
+			if !v.done && (curNode == nil || v.cost < curNode.cost || (v.cost == curNode.cost && k < cur)) {
+				cur, curNode = k, v
+			}
+		}
+		if curNode == nil {
+			return nil, false
+		}
+		curNode.done = true
+
+		// 到达目标格式时回溯路径
+		if cur == to {
+			var path []convertEdge
+			for k := cur; k != from; k = nodes[k].prev {
+				path = append([]convertEdge{nodes[k].edge}, path...)
+			}
+			return path, true
+		}
+
+		// 松弛相邻节点
+		for _, e := range convertGraph[cur] {
+			cost := curNode.cost + e.cost
+			if n, ok := nodes[e.to]; !ok || (!n.done && cost < n.cost) {
+				nodes[e.to] = &node{cost: cost, prev: cur, edge: e}
+			}
+		}
+	}
+}
+
+// ConvertPath 查询两种格式之间代价最小的转换路径
+//
+//	@param	from	源格式
+//	@param	to		目标格式
+//	@return	依次经过的格式（包含源格式和目标格式）
+//	@return	异常信息
+func ConvertPath(from, to Fourcc) ([]Fourcc, error) {
+	// 操作加读锁
+	convertMutex.RLock()
+	defer convertMutex.RUnlock()
+
+	// 查找路径
+	path, ok := findConvertPath(from, to)
+	if !ok {
+		return nil, errors.Join(ErrConvertPathNotFound, fmt.Errorf("%q to %q", from, to))
+	}
+	res := []Fourcc{from}
+	for _, e := range path {
+		res = append(res, e.to)
+	}
+	return res, nil
+}
+
+// Convert 将帧转换为目标格式（自动经由已注册的转换函数组成转换链）
+//
+//	@param	frame	源帧
+//	@param	target	目标格式
+//	@return	目标帧（源帧已是目标格式时直接返回源帧）
+//	@return	异常信息
+func Convert(frame *Frame, target Fourcc) (*Frame, error) {
+	if frame == nil {
+		return nil, errors.Join(ErrInvalidParam, errors.New("empty frame"))
+	}
+	if frame.Config.Format == target {
+		return frame, nil
+	}
+
+	// 查找路径（转换时不持有锁，允许转换函数内部再次调用Convert）
+	convertMutex.RLock()
+	path, ok := findConvertPath(frame.Config.Format, target)
+	convertMutex.RUnlock()
+	if !ok {
+		return nil, errors.Join(ErrConvertPathNotFound, fmt.Errorf("%q to %q", frame.Config.Format, target))
+	}
+
+	// 依次执行转换
+	cur := frame
+	for _, e := range path {
+		next, err := e.fn(cur)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			return nil, errors.Join(ErrInvalidParam, fmt.Errorf("converter to %q returned empty frame", e.to))
+		}
+		// 拷贝帧信息后再设置格式，避免原样返回源帧（或就地转换）的转换函数修改调用方的帧
+		out := *next
+		out.Config.Format = e.to
+		cur = &out
+	}
+	return cur, nil
+}
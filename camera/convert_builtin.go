@@ -0,0 +1,428 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// 内置转换代价（按每像素相对耗时估算）
+const (
+	convertCostCopy   = 1  // 平面拷贝或重排
+	convertCostSIMD   = 2  // 有平台加速的逐像素运算
+	convertCostPixel  = 3  // 纯Go逐像素运算
+	convertCostBayer  = 6  // 去马赛克
+	convertCostDecode = 20 // 压缩格式解码
+)
+
+// 注册内置转换函数
+func init() {
+	// RGB系列统一转换为RGBA32（非预乘透明度）
+	for format := range packedRGBLayouts {
+		RegisterConverter(format, FOURCC_RGBA32, convertCostPixel, rgbToRGBA32)
+	}
+	for format := range byteRGBLayouts {
+		if format != FOURCC_RGBA32 {
+			RegisterConverter(format, FOURCC_RGBA32, convertCostCopy, rgbToRGBA32)
+		}
+	}
+	RegisterConverter(FOURCC_RGBA32, FOURCC_RGB24, convertCostCopy, rgba32ToByteRGB(byteRGBLayouts[FOURCC_RGB24]))
+	RegisterConverter(FOURCC_RGBA32, FOURCC_BGR24, convertCostCopy, rgba32ToByteRGB(byteRGBLayouts[FOURCC_BGR24]))
+	RegisterConverter(FOURCC_RGBA32, FOURCC_YUV420, convertCostPixel, rgba32ToI420)
+
+	// 灰度
+	for _, format := range []Fourcc{FOURCC_Y10, FOURCC_Y12, FOURCC_Y14, FOURCC_Y10P, FOURCC_Y10BPACK} {
+		RegisterConverter(format, FOURCC_Y16, convertCostPixel, grayToY16)
+	}
+	for _, format := range []Fourcc{FOURCC_Y10, FOURCC_Y12, FOURCC_Y14, FOURCC_Y16, FOURCC_Y10P, FOURCC_Y10BPACK} {
+		RegisterConverter(format, FOURCC_GREY, convertCostPixel, grayToGrey)
+	}
+	RegisterConverter(FOURCC_GREY, FOURCC_RGBA32, convertCostCopy, greyToRGBA32)
+	RegisterConverter(FOURCC_GREY, FOURCC_YUV420, convertCostCopy, greyToI420)
+
+	// YUV
+	for format := range yuv422Layouts {
+		RegisterConverter(format, FOURCC_RGBA32, convertCostSIMD, yuv422ToRGBA32)
+		RegisterConverter(format, FOURCC_YUV420, convertCostCopy, yuv422ToI420)
+	}
+	RegisterConverter(FOURCC_NV12, FOURCC_YUV420, convertCostCopy, nv12ToI420)
+	RegisterConverter(FOURCC_YUV420, FOURCC_NV12, convertCostCopy, i420ToNV12)
+	RegisterConverter(FOURCC_NV21, FOURCC_YUV420, convertCostCopy, nv21ToI420)
+	RegisterConverter(FOURCC_YVU420, FOURCC_YUV420, convertCostCopy, swapI420Planes)
+	RegisterConverter(FOURCC_YUV420, FOURCC_YVU420, convertCostCopy, swapI420Planes)
+	RegisterConverter(FOURCC_YUV420, FOURCC_RGBA32, convertCostPixel, i420ToRGBA32)
+
+	// Bayer
+	for format := range bayerPatterns {
+		RegisterConverter(format, FOURCC_RGB24, convertCostBayer, bayerToRGB24)
+	}
+
+	// 压缩格式
+	RegisterConverter(FOURCC_MJPEG, FOURCC_YUV420, convertCostDecode, jpegToI420)
+	RegisterConverter(FOURCC_JPEG, FOURCC_YUV420, convertCostDecode, jpegToI420)
+}
+
+// 创建与源帧尺寸一致的新帧（格式由转换图设置）
+func newConvertedFrame(src *Frame, data []byte) *Frame {
+	return &Frame{
+		Data:        data,
+		Config:      src.Config,
+		Orientation: RGBOrientationTopDown,
+	}
+}
+
+// ------------------------------------------------ RGB ------------------------------------------------ //
+
+// RGB系列转RGBA32
+func rgbToRGBA32(src *Frame) (*Frame, error) {
+	img, err := decodeRGBA(src.Data, &src.Config, src.Orientation, putStraight)
+	if err != nil {
+		return nil, err
+	}
+	return newConvertedFrame(src, img.Pix), nil
+}
+
+// RGBA32转字节对齐的RGB格式（丢弃透明通道）
+func rgba32ToByteRGB(layout byteRGBLayout) ConvertFunc {
+	return func(src *Frame) (*Frame, error) {
+		img, err := decodeRGBA(src.Data, &src.Config, src.Orientation, putStraight)
+		if err != nil {
+			return nil, err
+		}
+		res := make([]byte, len(img.Pix)/4*layout.bytes)
+		for i := 0; i < len(img.Pix)/4; i++ {
+			px := res[i*layout.bytes:]
+			px[layout.r], px[layout.g], px[layout.b] = img.Pix[4*i], img.Pix[4*i+1], img.Pix[4*i+2]
+		}
+		return newConvertedFrame(src, res), nil
+	}
+}
+
+// 将一个RGB像素转换为YUV（BT.601有限范围）
+func rgbToYUV(r, g, b uint8) (uint8, uint8, uint8) {
+	ri, gi, bi := int32(r), int32(g), int32(b)
+	return uint8((66*ri+129*gi+25*bi+128)>>8 + 16),
+		uint8((-38*ri-74*gi+112*bi+128)>>8 + 128),
+		uint8((112*ri-94*gi-18*bi+128)>>8 + 128)
+}
+
+// RGBA32转I420（色度取2x2像素平均值）
+func rgba32ToI420(src *Frame) (*Frame, error) {
+	img, err := decodeRGBA(src.Data, &src.Config, src.Orientation, putStraight)
+	if err != nil {
+		return nil, err
+	}
+
+	// 逐像素计算亮度，逐2x2块计算色度
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	ySize, cSize := yuv420PlaneSize(&src.Config)
+	cw := (w + 1) / 2
+	res := make([]byte, ySize+2*cSize)
+	for y := 0; y < h; y += 2 {
+		for x := 0; x < w; x += 2 {
+			var sumU, sumV, n int
+			for dy := 0; dy < 2 && y+dy < h; dy++ {
+				for dx := 0; dx < 2 && x+dx < w; dx++ {
+					px := img.Pix[img.PixOffset(x+dx, y+dy):]
+					yv, u, v := rgbToYUV(px[0], px[1], px[2])
+					res[(y+dy)*w+x+dx] = yv
+					sumU += int(u)
+					sumV += int(v)
+					n++
+				}
+			}
+			res[ySize+y/2*cw+x/2] = uint8((sumU + n/2) / n)
+			res[ySize+cSize+y/2*cw+x/2] = uint8((sumV + n/2) / n)
+		}
+	}
+	return newConvertedFrame(src, res), nil
+}
+
+// ------------------------------------------------ 灰度 ------------------------------------------------ //
+
+// 高位深灰度转Y16（小端序）
+func grayToY16(src *Frame) (*Frame, error) {
+	img, err := DecodeGray16(src.Data, &src.Config)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, len(img.Pix))
+	for i := 0; i < len(res); i += 2 {
+		res[i], res[i+1] = img.Pix[i+1], img.Pix[i]
+	}
+	return newConvertedFrame(src, res), nil
+}
+
+// 高位深灰度转8位灰度（全范围线性映射）
+func grayToGrey(src *Frame) (*Frame, error) {
+	img, err := DecodeGray16(src.Data, &src.Config)
+	if err != nil {
+		return nil, err
+	}
+	return newConvertedFrame(src, Gray16ToGray(img, WindowLevel{}).Pix), nil
+}
+
+// 检查8位灰度帧并获取行跨度
+func greyStride(src *Frame) (int, error) {
+	if src.Config.Format != FOURCC_GREY {
+		return 0, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q is not GREY", src.Config.Format))
+	}
+	return frameStride(src.Data, &src.Config, int(src.Config.Width))
+}
+
+// 8位灰度转RGBA32
+func greyToRGBA32(src *Frame) (*Frame, error) {
+	stride, err := greyStride(src)
+	if err != nil {
+		return nil, err
+	}
+	w, h := int(src.Config.Width), int(src.Config.Height)
+	res := make([]byte, w*h*4)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := src.Data[y*stride+x]
+			px := res[4*(y*w+x):]
+			px[0], px[1], px[2], px[3] = v, v, v, 0xff
+		}
+	}
+	return newConvertedFrame(src, res), nil
+}
+
+// 8位灰度转I420（全范围亮度压缩到有限范围，色度置中）
+func greyToI420(src *Frame) (*Frame, error) {
+	stride, err := greyStride(src)
+	if err != nil {
+		return nil, err
+	}
+	w, h := int(src.Config.Width), int(src.Config.Height)
+	ySize, cSize := yuv420PlaneSize(&src.Config)
+	res := make([]byte, ySize+2*cSize)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			res[y*w+x] = fullToLimitedY(src.Data[y*stride+x])
+		}
+	}
+	for i := ySize; i < len(res); i++ {
+		res[i] = 0x80
+	}
+	return newConvertedFrame(src, res), nil
+}
+
+// 全范围亮度转有限范围亮度
+func fullToLimitedY(v uint8) uint8 {
+	return uint8(16 + (int(v)*219+127)/255)
+}
+
+// 全范围色度转有限范围色度
+func fullToLimitedC(v uint8) uint8 {
+	c := (int(v)-128)*224 + 127
+	if c < 0 {
+		c -= 254
+	}
+	return uint8(128 + c/255)
+}
+
+// ------------------------------------------------ YUV ------------------------------------------------ //
+
+// 打包YUV 4:2:2转RGBA32
+func yuv422ToRGBA32(src *Frame) (*Frame, error) {
+	img, err := DecodeYUV422(src.Data, &src.Config)
+	if err != nil {
+		return nil, err
+	}
+	return newConvertedFrame(src, img.Pix), nil
+}
+
+// 打包YUV 4:2:2转I420（色度取上下两行平均值）
+func yuv422ToI420(src *Frame) (*Frame, error) {
+	layout, ok := yuv422Layouts[src.Config.Format]
+	if !ok {
+		return nil, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q is not packed yuv 4:2:2", src.Config.Format))
+	}
+	w, h := int(src.Config.Width), int(src.Config.Height)
+	cw := (w + 1) / 2
+	stride, err := frameStride(src.Data, &src.Config, cw*4)
+	if err != nil {
+		return nil, err
+	}
+
+	// 逐行拆分
+	ySize, cSize := yuv420PlaneSize(&src.Config)
+	res := make([]byte, ySize+2*cSize)
+	for y := 0; y < h; y++ {
+		row := src.Data[y*stride:]
+		// 亮度
+		for x := 0; x < w; x++ {
+			res[y*w+x] = row[x/2*4+layout[x%2*2]]
+		}
+		// 色度（偶数行与下一行平均）
+		if y%2 == 1 {
+			continue
+		}
+		next := row
+		if y+1 < h {
+			next = src.Data[(y+1)*stride:]
+		}
+		for cx := 0; cx < cw; cx++ {
+			res[ySize+y/2*cw+cx] = uint8((int(row[cx*4+layout[1]]) + int(next[cx*4+layout[1]]) + 1) / 2)
+			res[ySize+cSize+y/2*cw+cx] = uint8((int(row[cx*4+layout[3]]) + int(next[cx*4+layout[3]]) + 1) / 2)
+		}
+	}
+	return newConvertedFrame(src, res), nil
+}
+
+// NV12转I420
+func nv12ToI420(src *Frame) (*Frame, error) {
+	res, err := ConvertNV12ToI420(src.Data, &src.Config)
+	if err != nil {
+		return nil, err
+	}
+	return newConvertedFrame(src, res), nil
+}
+
+// I420转NV12
+func i420ToNV12(src *Frame) (*Frame, error) {
+	res, err := ConvertI420ToNV12(src.Data, &src.Config)
+	if err != nil {
+		return nil, err
+	}
+	return newConvertedFrame(src, res), nil
+}
+
+// NV21转I420（色度交织顺序与NV12相反）
+func nv21ToI420(src *Frame) (*Frame, error) {
+	if err := checkYUV420(src.Data, &src.Config, FOURCC_NV21); err != nil {
+		return nil, err
+	}
+	ySize, cSize := yuv420PlaneSize(&src.Config)
+	res := make([]byte, ySize+2*cSize)
+	copy(res, src.Data[:ySize])
+	deinterleaveUV(res[ySize+cSize:], res[ySize:ySize+cSize], src.Data[ySize:ySize+2*cSize])
+	return newConvertedFrame(src, res), nil
+}
+
+// I420与YV12互转（交换U、V平面）
+func swapI420Planes(src *Frame) (*Frame, error) {
+	if err := checkYUV420(src.Data, &src.Config, src.Config.Format); err != nil {
+		return nil, err
+	}
+	ySize, cSize := yuv420PlaneSize(&src.Config)
+	res := make([]byte, ySize+2*cSize)
+	copy(res, src.Data[:ySize])
+	copy(res[ySize:], src.Data[ySize+cSize:ySize+2*cSize])
+	copy(res[ySize+cSize:], src.Data[ySize:ySize+cSize])
+	return newConvertedFrame(src, res), nil
+}
+
+// I420转RGBA32
+func i420ToRGBA32(src *Frame) (*Frame, error) {
+	if err := checkYUV420(src.Data, &src.Config, FOURCC_YUV420); err != nil {
+		return nil, err
+	}
+	w, h := int(src.Config.Width), int(src.Config.Height)
+	ySize, cSize := yuv420PlaneSize(&src.Config)
+	cw := (w + 1) / 2
+	res := make([]byte, w*h*4)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			ci := y/2*cw + x/2
+			px := res[4*(y*w+x):]
+			px[0], px[1], px[2] = yuvToRGB(src.Data[y*w+x], src.Data[ySize+ci], src.Data[ySize+cSize+ci])
+			px[3] = 0xff
+		}
+	}
+	return newConvertedFrame(src, res), nil
+}
+
+// ------------------------------------------------ Bayer ------------------------------------------------ //
+
+// Bayer格式2x2单元的颜色排列（0:R 1:G 2:B，依次为左上、右上、左下、右下）
+var bayerPatterns = map[Fourcc][4]int{
+	FOURCC_SBGGR8: {2, 1, 1, 0},
+	FOURCC_SGBRG8: {1, 2, 0, 1},
+	FOURCC_SGRBG8: {1, 0, 2, 1},
+	FOURCC_SRGGB8: {0, 1, 1, 2},
+}
+
+// 8位Bayer转RGB24（双线性插值：缺失通道取3x3邻域内同色像素的平均值）
+func bayerToRGB24(src *Frame) (*Frame, error) {
+	pattern, ok := bayerPatterns[src.Config.Format]
+	if !ok {
+		return nil, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q is not 8-bit bayer", src.Config.Format))
+	}
+	w, h := int(src.Config.Width), int(src.Config.Height)
+	stride, err := frameStride(src.Data, &src.Config, w)
+	if err != nil {
+		return nil, err
+	}
+
+	// 逐像素插值
+	res := make([]byte, w*h*3)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var sum, n [3]int
+			for dy := -1; dy <= 1; dy++ {
+				sy := y + dy
+				if sy < 0 || sy >= h {
+					continue
+				}
+				for dx := -1; dx <= 1; dx++ {
+					sx := x + dx
+					if sx < 0 || sx >= w {
+						continue
+					}
+					c := pattern[sy%2*2+sx%2]
+					sum[c] += int(src.Data[sy*stride+sx])
+					n[c]++
+				}
+			}
+			px := res[3*(y*w+x):]
+			for c := 0; c < 3; c++ {
+				if n[c] > 0 {
+					px[c] = uint8((sum[c] + n[c]/2) / n[c])
+				}
+			}
+			// 本像素的颜色直接取原值
+			px[pattern[y%2*2+x%2]] = src.Data[y*stride+x]
+		}
+	}
+	return newConvertedFrame(src, res), nil
+}
+
+// ------------------------------------------------ 压缩格式 ------------------------------------------------ //
+
+// MJPEG/JPEG转I420
+func jpegToI420(src *Frame) (*Frame, error) {
+	img, err := DecodeMJPEG(src.Data)
+	if err != nil {
+		return nil, err
+	}
+	return ycbcrToI420(img, src.Config), nil
+}
+
+// 将YCbCr图像转换为I420帧（JFIF为全范围，转换为有限范围以匹配其他YUV格式）
+//
+//	@param	img	YCbCr图像
+//	@param	cfg	帧信息（宽高以图像实际尺寸为准）
+//	@return	I420帧
+func ycbcrToI420(img *image.YCbCr, cfg DeviceConfig) *Frame {
+	b := img.Rect
+	cfg.Width, cfg.Height = uint32(b.Dx()), uint32(b.Dy())
+	w, h := b.Dx(), b.Dy()
+	ySize, cSize := yuv420PlaneSize(&cfg)
+	cw := (w + 1) / 2
+	res := make([]byte, ySize+2*cSize)
+
+	// 亮度逐像素拷贝，色度按2x2块左上角采样
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			res[y*w+x] = fullToLimitedY(img.Y[img.YOffset(b.Min.X+x, b.Min.Y+y)])
+			if x%2 == 0 && y%2 == 0 {
+				ci := img.COffset(b.Min.X+x, b.Min.Y+y)
+				res[ySize+y/2*cw+x/2] = fullToLimitedC(img.Cb[ci])
+				res[ySize+cSize+y/2*cw+x/2] = fullToLimitedC(img.Cr[ci])
+			}
+		}
+	}
+	return &Frame{Data: res, Config: cfg, Orientation: RGBOrientationTopDown}
+}
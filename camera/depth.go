@@ -0,0 +1,302 @@
+package camera
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+)
+
+// DefaultDepthScale 默认深度单位（米/单位），RealSense系列设备默认为1毫米
+const DefaultDepthScale = 0.001
+
+// DepthFrame 16位深度帧
+type DepthFrame struct {
+	Width  int      // 宽度
+	Height int      // 高度
+	Data   []uint16 // 深度原始值（按行存储，0表示无效）
+	Scale  float64  // 深度单位（米/单位）
+}
+
+// NewDepthFrame 从Z16格式的帧创建深度帧
+//
+//	@param	data	帧数据
+//	@param	info	帧信息
+//	@param	scale	深度单位（米/单位，小于等于0时使用DefaultDepthScale）
+//	@return	深度帧
+//	@return	异常信息
+func NewDepthFrame(data []byte, info *DeviceConfig, scale float64) (*DepthFrame, error) {
+	// 检查格式
+	if info == nil {
+		return nil, errors.Join(ErrFrameSizeMismatch, errors.New("empty frame size"))
+	}
+	if info.Format != FOURCC_Z16 {
+		return nil, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q is not depth", info.Format))
+	}
+	width, height := int(info.Width), int(info.Height)
+	stride, err := frameStride(data, info, width*2)
+	if err != nil {
+		return nil, err
+	}
+	if scale <= 0 {
+		scale = DefaultDepthScale
+	}
+
+	// 按小端序拷贝深度值
+	res := &DepthFrame{
+		Width:  width,
+		Height: height,
+		Data:   make([]uint16, width*height),
+		Scale:  scale,
+	}
+	for y := 0; y < height; y++ {
+		row := data[y*stride:]
+		for x := 0; x < width; x++ {
+			res.Data[y*width+x] = binary.LittleEndian.Uint16(row[2*x:])
+		}
+	}
+
+	// OK
+	return res, nil
+}
+
+// Clone 克隆深度帧
+func (p *DepthFrame) Clone() *DepthFrame {
+	if p == nil {
+		return nil
+	}
+	res := *p
+	res.Data = append([]uint16(nil), p.Data...)
+	return &res
+}
+
+// At 获取指定像素的深度原始值
+func (p *DepthFrame) At(x, y int) uint16 {
+	if x < 0 || y < 0 || x >= p.Width || y >= p.Height {
+		return 0
+	}
+	return p.Data[y*p.Width+x]
+}
+
+// Meters 获取指定像素的深度（米）
+func (p *DepthFrame) Meters(x, y int) float64 {
+	return float64(p.At(x, y)) * p.Scale
+}
+
+// 获取有效深度原始值的范围
+func (p *DepthFrame) validRange() (uint16, uint16) {
+	lo, hi := uint16(math.MaxUint16), uint16(0)
+	for _, v := range p.Data {
+		if v == 0 {
+			continue
+		}
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	return lo, hi
+}
+
+// Colormap 深度伪彩色色表
+type Colormap int
+
+const (
+	ColormapJet   Colormap = iota // 蓝-青-黄-红
+	ColormapTurbo                 // Google Turbo
+	ColormapGrey                  // 近白远黑
+)
+
+// 生成256级色表
+func (c Colormap) table() [256][3]uint8 {
+	var res [256][3]uint8
+	for i := range res {
+		t := float64(i) / 255
+		var r, g, b float64
+		switch c {
+		case ColormapTurbo:
+			// Turbo的多项式近似
+			r = 0.13572138 + t*(4.61539260+t*(-42.66032258+t*(132.13108234+t*(-152.94239396+t*59.28637943))))
+			g = 0.09140261 + t*(2.19418839+t*(4.84296658+t*(-14.18503333+t*(4.27729857+t*2.82956604))))
+			b = 0.10667330 + t*(12.64194608+t*(-60.58204836+t*(110.36276771+t*(-89.90310912+t*27.34824973))))
+		case ColormapGrey:
+			r, g, b = 1-t, 1-t, 1-t
+		default:
+			r = 1.5 - math.Abs(4*t-3)
+			g = 1.5 - math.Abs(4*t-2)
+			b = 1.5 - math.Abs(4*t-1)
+		}
+		res[i] = [3]uint8{unitToByte(r), unitToByte(g), unitToByte(b)}
+	}
+	return res
+}
+
+// 将0~1的浮点值转换为字节
+func unitToByte(v float64) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 1 {
+		return 0xff
+	}
+	return uint8(v*255 + 0.5)
+}
+
+// Colorize 将深度帧渲染为伪彩色图像（无效像素为黑色）
+//
+//	@param	cmap	色表
+//	@param	near	最近距离（米）
+//	@param	far		最远距离（米，不大于near时根据有效深度自动计算范围）
+//	@return	伪彩色图像
+func (p *DepthFrame) Colorize(cmap Colormap, near, far float64) *image.RGBA {
+	// 计算映射范围（原始值）
+	lo, hi := near/p.Scale, far/p.Scale
+	if far <= near {
+		rawLo, rawHi := p.validRange()
+		lo, hi = float64(rawLo), float64(rawHi)
+	}
+	span := hi - lo
+	if span <= 0 {
+		span = 1
+	}
+
+	// 逐像素着色
+	table := cmap.table()
+	img := image.NewRGBA(image.Rect(0, 0, p.Width, p.Height))
+	for i, v := range p.Data {
+		px := img.Pix[4*i : 4*i+4]
+		px[3] = 0xff
+		if v == 0 {
+			continue
+		}
+		idx := (float64(v) - lo) / span * 255
+		if idx < 0 {
+			idx = 0
+		} else if idx > 255 {
+			idx = 255
+		}
+		c := table[int(idx+0.5)]
+		px[0], px[1], px[2] = c[0], c[1], c[2]
+	}
+	return img
+}
+
+// HoleFillMode 深度空洞填充模式
+type HoleFillMode int
+
+const (
+	HoleFillFromLeft HoleFillMode = iota // 使用左侧像素填充
+	HoleFillFarthest                     // 使用相邻有效像素中最远的值填充
+	HoleFillNearest                      // 使用相邻有效像素中最近的值填充
+)
+
+// FillHoles 填充深度帧中的无效像素
+//
+// 按行扫描，使用已处理过的左、左上、上、右上像素作为候选
+//
+//	@param	mode	填充模式
+//	@return	填充后的深度帧
+func (p *DepthFrame) FillHoles(mode HoleFillMode) *DepthFrame {
+	res := p.Clone()
+	for y := 0; y < res.Height; y++ {
+		for x := 0; x < res.Width; x++ {
+			i := y*res.Width + x
+			if res.Data[i] != 0 {
+				continue
+			}
+			// 左侧填充只看左侧像素
+			if mode == HoleFillFromLeft {
+				res.Data[i] = res.At(x-1, y)
+				continue
+			}
+			// 从相邻像素中挑选
+			var fill uint16
+			for _, v := range [4]uint16{res.At(x-1, y), res.At(x-1, y-1), res.At(x, y-1), res.At(x+1, y-1)} {
+				if v == 0 {
+					continue
+				}
+				if fill == 0 ||
+					(mode == HoleFillFarthest && v > fill) ||
+					(mode == HoleFillNearest && v < fill) {
+					fill = v
+				}
+			}
+			res.Data[i] = fill
+		}
+	}
+	return res
+}
+
+// Intrinsics 针孔相机内参
+type Intrinsics struct {
+	Fx, Fy float64 // 焦距（像素）
+	Cx, Cy float64 // 主点（像素）
+}
+
+// Point3 三维点（米）
+type Point3 struct {
+	X, Y, Z float32
+}
+
+// PointCloud 根据相机内参将深度帧反投影为点云（忽略无效像素）
+//
+//	@param	intr	相机内参
+//	@return	点云
+//	@return	异常信息
+func (p *DepthFrame) PointCloud(intr Intrinsics) ([]Point3, error) {
+	if intr.Fx == 0 || intr.Fy == 0 {
+		return nil, errors.Join(ErrInvalidParam, errors.New("zero focal length"))
+	}
+
+	res := make([]Point3, 0, len(p.Data))
+	for y := 0; y < p.Height; y++ {
+		for x := 0; x < p.Width; x++ {
+			v := p.Data[y*p.Width+x]
+			if v == 0 {
+				continue
+			}
+			z := float64(v) * p.Scale
+			res = append(res, Point3{
+				X: float32((float64(x) - intr.Cx) * z / intr.Fx),
+				Y: float32((float64(y) - intr.Cy) * z / intr.Fy),
+				Z: float32(z),
+			})
+		}
+	}
+	return res, nil
+}
+
+// WritePLY 将点云以二进制小端序PLY格式写出
+//
+//	@param	w		输出流
+//	@param	intr	相机内参
+//	@return	异常信息
+func (p *DepthFrame) WritePLY(w io.Writer, intr Intrinsics) error {
+	// 生成点云
+	points, err := p.PointCloud(intr)
+	if err != nil {
+		return err
+	}
+
+	// 写文件头
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "ply\nformat binary_little_endian 1.0\nelement vertex %d\n", len(points))
+	fmt.Fprint(bw, "property float x\nproperty float y\nproperty float z\nend_header\n")
+
+	// 写顶点
+	var buf [12]byte
+	for _, pt := range points {
+		binary.LittleEndian.PutUint32(buf[0:], math.Float32bits(pt.X))
+		binary.LittleEndian.PutUint32(buf[4:], math.Float32bits(pt.Y))
+		binary.LittleEndian.PutUint32(buf[8:], math.Float32bits(pt.Z))
+		if _, err := bw.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -2,9 +2,13 @@ package camera
 
 // Device 相机设备信息
 type Device struct {
-	ID           string // 相机ID
-	Name         string // 相机名称
-	SymbolicLink string // 相机系统路径
+	ID           string         // 相机ID（优先由持久化标识属性计算，不随/dev/videoN编号变化）
+	LegacyID     string         // 旧版相机ID（md5(devicePath + name)，仍可用于查询）
+	Name         string         // 相机名称
+	SymbolicLink string         // 相机系统路径
+	Identity     DeviceIdentity // 持久化标识属性（USB VID:PID、序列号、端口路径等）
+	Metadata     DeviceMetadata // 硬件信息（驱动、总线、制造商、固件版本、能力标志等）
+	NodeType     DeviceNodeType // 节点类型
 }
 
 // Clone 克隆相机设备信息
@@ -14,7 +18,11 @@ func (p *Device) Clone() *Device {
 	}
 	return &Device{
 		ID:           p.ID,
+		LegacyID:     p.LegacyID,
 		Name:         p.Name,
 		SymbolicLink: p.SymbolicLink,
+		Identity:     p.Identity,
+		Metadata:     p.Metadata,
+		NodeType:     p.NodeType,
 	}
 }
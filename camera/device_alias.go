@@ -0,0 +1,225 @@
+package camera
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DeviceMatchRule 设备匹配规则（所有非空条件都满足时匹配）
+type DeviceMatchRule struct {
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`           // 相机名称正则表达式
+	Serial   string `json:"serial,omitempty" yaml:"serial,omitempty"`       // USB序列号
+	VIDPID   string `json:"vidpid,omitempty" yaml:"vidpid,omitempty"`       // USB厂商ID与产品ID（如"046d:0825"）
+	PortPath string `json:"port_path,omitempty" yaml:"port_path,omitempty"` // 物理端口路径（/dev/v4l/by-path下的链接名称）
+}
+
+// DeviceAlias 设备别名
+type DeviceAlias struct {
+	Alias string          `json:"alias" yaml:"alias"` // 别名（如front-door）
+	Match DeviceMatchRule `json:"match" yaml:"match"` // 匹配规则
+}
+
+// DeviceAliases 设备别名列表（并发安全，可直接序列化为JSON或YAML）
+type DeviceAliases struct {
+	Aliases []DeviceAlias `json:"aliases" yaml:"aliases"`
+
+	mutex sync.Mutex // 保护Set与并发查询
+}
+
+// VID:PID格式
+var vidpidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{4}:[0-9a-fA-F]{4}$`)
+
+// Validate 检查别名与匹配规则
+//
+//	@return	异常信息
+func (p *DeviceAliases) Validate() error {
+	_, err := compileDeviceAliases(p.snapshot())
+	return err
+}
+
+// 获取当前别名列表
+func (p *DeviceAliases) snapshot() []DeviceAlias {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.Aliases
+}
+
+// 检查别名并编译名称正则表达式
+//
+//	@param	aliases	别名列表
+//	@return	别名对应的名称正则表达式
+//	@return	异常信息
+func compileDeviceAliases(aliases []DeviceAlias) (map[string]*regexp.Regexp, error) {
+	names := make(map[string]*regexp.Regexp, len(aliases))
+	seen := make(map[string]bool, len(aliases))
+	for _, v := range aliases {
+		if v.Alias == "" {
+			return nil, errors.Join(ErrInvalidParam, errors.New("empty device alias"))
+		}
+		if seen[v.Alias] {
+			return nil, errors.Join(ErrInvalidParam, fmt.Errorf("duplicate device alias %q", v.Alias))
+		}
+		seen[v.Alias] = true
+		if v.Match == (DeviceMatchRule{}) {
+			return nil, errors.Join(ErrInvalidParam, fmt.Errorf("device alias %q has no match rule", v.Alias))
+		}
+		if v.Match.VIDPID != "" && !vidpidRegexp.MatchString(v.Match.VIDPID) {
+			return nil, errors.Join(ErrInvalidParam, fmt.Errorf("device alias %q: invalid vidpid %q", v.Alias, v.Match.VIDPID))
+		}
+		if v.Match.Name != "" {
+			re, err := regexp.Compile(v.Match.Name)
+			if err != nil {
+				return nil, errors.Join(ErrInvalidParam, fmt.Errorf("device alias %q: %w", v.Alias, err))
+			}
+			names[v.Alias] = re
+		}
+	}
+	return names, nil
+}
+
+// Set 添加或替换别名
+//
+//	@param	alias	别名
+//	@param	rule	匹配规则
+//	@return	异常信息（规则无效时别名列表保持不变）
+func (p *DeviceAliases) Set(alias string, rule DeviceMatchRule) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// 在副本上修改，避免影响正在进行的查询
+	next := make([]DeviceAlias, 0, len(p.Aliases)+1)
+	replaced := false
+	for _, v := range p.Aliases {
+		if v.Alias == alias {
+			v.Match = rule
+			replaced = true
+		}
+		next = append(next, v)
+	}
+	if !replaced {
+		next = append(next, DeviceAlias{Alias: alias, Match: rule})
+	}
+	if _, err := compileDeviceAliases(next); err != nil {
+		return err
+	}
+	p.Aliases = next
+	return nil
+}
+
+// 设备是否满足匹配规则
+func (r *DeviceMatchRule) match(device *Device, name *regexp.Regexp) bool {
+	if name != nil && !name.MatchString(device.Name) {
+		return false
+	}
+	if r.Serial != "" && r.Serial != device.Identity.Serial {
+		return false
+	}
+	if r.VIDPID != "" && !strings.EqualFold(r.VIDPID, device.Identity.VIDPID()) {
+		return false
+	}
+	if r.PortPath != "" && r.PortPath != device.Identity.PortPath {
+		return false
+	}
+	return true
+}
+
+// Lookup 通过相机ID（含旧版ID）或别名在列表中查询相机
+//
+//	@param	list	相机列表
+//	@param	id		相机ID或别名
+//	@return	相机信息
+//	@return	异常信息（别名匹配到多个设备时返回ErrDeviceAliasAmbiguous）
+func (p *DeviceAliases) Lookup(list DeviceList, id string) (*Device, error) {
+	// 优先按相机ID查询
+	res, err := list.Get(id)
+	if err == nil || p == nil {
+		return res, err
+	}
+
+	// 按别名查询
+	aliases := p.snapshot()
+	names, err := compileDeviceAliases(aliases)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range aliases {
+		if v.Alias != id {
+			continue
+		}
+		var matched DeviceList
+		for _, device := range list {
+			if v.Match.match(device, names[v.Alias]) {
+				matched = append(matched, device)
+			}
+		}
+		switch len(matched) {
+		case 0:
+			return nil, ErrDeviceNotFound
+		case 1:
+			return matched[0].Clone(), nil
+		default:
+			return nil, ErrDeviceAliasAmbiguous
+		}
+	}
+	return nil, ErrDeviceNotFound
+}
+
+// LoadDeviceAliases 从文件加载别名（.json使用JSON格式，其余使用YAML格式）
+//
+//	@param	path	文件路径
+//	@return	别名列表
+//	@return	异常信息
+func LoadDeviceAliases(path string) (*DeviceAliases, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	res := &DeviceAliases{}
+	if isJSONFile(path) {
+		err = json.Unmarshal(data, res)
+	} else {
+		err = yaml.Unmarshal(data, res)
+	}
+	if err != nil {
+		return nil, errors.Join(ErrInvalidParam, err)
+	}
+	if err := res.Validate(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Save 保存别名到文件（.json使用JSON格式，其余使用YAML格式）
+//
+//	@param	path	文件路径
+//	@return	异常信息
+func (p *DeviceAliases) Save(path string) error {
+	out := struct {
+		Aliases []DeviceAlias `json:"aliases" yaml:"aliases"`
+	}{p.snapshot()}
+
+	var data []byte
+	var err error
+	if isJSONFile(path) {
+		data, err = json.MarshalIndent(out, "", "  ")
+	} else {
+		data, err = yaml.Marshal(out)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// 是否为JSON文件
+func isJSONFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
@@ -0,0 +1,63 @@
+//go:build linux
+
+package camera
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// VIDIOC_QUERYCAP = _IOR('V', 0, struct v4l2_capability)
+const vidiocQueryCap = 0x80685600
+
+// V4L2_CAP_DEVICE_CAPS 驱动填写了device_caps字段
+const v4l2CapDeviceCaps = 0x80000000
+
+// struct v4l2_capability
+type v4l2Capability struct {
+	driver       [16]byte
+	card         [32]byte
+	busInfo      [32]byte
+	version      uint32
+	capabilities uint32
+	deviceCaps   uint32
+	reserved     [3]uint32
+}
+
+// 通过VIDIOC_QUERYCAP查询节点能力
+//
+//	@param	devicePath	设备节点路径
+//	@return	查询结果
+//	@return	是否查询成功
+func queryDeviceCaps(devicePath string) (deviceCapsInfo, bool) {
+	file, err := os.OpenFile(devicePath, os.O_RDWR|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return deviceCapsInfo{}, false
+	}
+	defer file.Close()
+
+	var capability v4l2Capability
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), vidiocQueryCap, uintptr(unsafe.Pointer(&capability)))
+	if errno != 0 {
+		return deviceCapsInfo{}, false
+	}
+
+	// 优先使用节点自身的能力
+	caps := capability.capabilities
+	if caps&v4l2CapDeviceCaps != 0 {
+		caps = capability.deviceCaps
+	}
+	cstr := func(b []byte) string {
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		return string(b)
+	}
+	return deviceCapsInfo{
+		driver:  cstr(capability.driver[:]),
+		busInfo: cstr(capability.busInfo[:]),
+		caps:    DeviceCaps(caps),
+	}, true
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package camera
+
+// 非Linux平台不支持VIDIOC_QUERYCAP
+func queryDeviceCaps(devicePath string) (deviceCapsInfo, bool) {
+	return deviceCapsInfo{}, false
+}
@@ -8,7 +8,7 @@ func NewFourccFromNumber(num uint32) Fourcc {
 	return Fourcc(string([]byte{byte(num), byte(num >> 8), byte(num >> 16), byte(num >> 24)}))
 }
 
-// Number 返回FOURCC的数值
+// Number 返回FOURCC的数值（无效的FOURCC返回0，传给相机库前需先调用Validate检查）
 func (p Fourcc) Number() uint32 {
 	if len(p) != 4 {
 		return 0
@@ -254,19 +254,26 @@ const (
 
 // DeviceConfig 相机配置信息
 type DeviceConfig struct {
-	Width  uint32 // 相机支持的分辨率宽度
-	Height uint32 // 相机支持的分辨率高度
-	FPS    uint32 // 相机在该分辨率下支持的帧率
-	Format Fourcc // 相机支持的格式
+	Width    uint32        // 相机支持的分辨率宽度
+	Height   uint32        // 相机支持的分辨率高度
+	FPS      uint32        // 相机在该分辨率下支持的帧率（四舍五入后的整数帧率，保留用于兼容，精确值见Interval）
+	Interval FrameInterval // 相机在该分辨率下支持的帧间隔（与FPS不一致时以FPS为准，见FrameInterval方法；相机库枚举的配置目前只有整数帧率）
+	Format   Fourcc        // 相机支持的格式
 }
 
-// NewDeviceConfig 构造配置信息
+// NewDeviceConfig 构造配置信息（整数帧率）
 func NewDeviceConfig(w, h, fps uint32, format Fourcc) DeviceConfig {
+	return NewDeviceConfigWithInterval(w, h, FrameIntervalFromFPS(fps), format)
+}
+
+// NewDeviceConfigWithInterval 构造配置信息（分数帧间隔，如NTSC的1001/30000）
+func NewDeviceConfigWithInterval(w, h uint32, interval FrameInterval, format Fourcc) DeviceConfig {
 	return DeviceConfig{
-		Width:  w,
-		Height: h,
-		FPS:    fps,
-		Format: format,
+		Width:    w,
+		Height:   h,
+		FPS:      interval.FPS(),
+		Interval: interval,
+		Format:   format,
 	}
 }
 
@@ -276,14 +283,31 @@ func (p *DeviceConfig) Clone() *DeviceConfig {
 		return nil
 	}
 	return &DeviceConfig{
-		Width:  p.Width,
-		Height: p.Height,
-		FPS:    p.FPS,
-		Format: p.Format,
+		Width:    p.Width,
+		Height:   p.Height,
+		FPS:      p.FPS,
+		Interval: p.Interval,
+		Format:   p.Format,
 	}
 }
 
-// Eq 两个配置信息是否一样
+// FrameInterval 实际使用的帧间隔
+//
+// Interval为0，或FPS不为0且与Interval换算出的整数帧率不一致时（如复制配置后只修改了FPS），
+// 由FPS换算，兼容只读写FPS字段的旧代码
+func (p DeviceConfig) FrameInterval() FrameInterval {
+	if p.Interval.IsZero() || (p.FPS != 0 && p.Interval.FPS() != p.FPS) {
+		return FrameIntervalFromFPS(p.FPS)
+	}
+	return p.Interval
+}
+
+// FrameRate 精确帧率
+func (p DeviceConfig) FrameRate() float64 {
+	return p.FrameInterval().Rate()
+}
+
+// Eq 两个配置信息是否一样（帧间隔按数值比较）
 func (p *DeviceConfig) Eq(v *DeviceConfig) bool {
 	if p == nil && v == nil {
 		return true
@@ -293,11 +317,11 @@ func (p *DeviceConfig) Eq(v *DeviceConfig) bool {
 	}
 	return p.Width == v.Width &&
 		p.Height == v.Height &&
-		p.FPS == v.FPS &&
+		p.FrameInterval().Cmp(v.FrameInterval()) == 0 &&
 		p.Format == v.Format
 }
 
 // IsZero 检查配置信息是否为0值（不关心Format）
 func (p *DeviceConfig) IsZero() bool {
-	return p.Width == 0 && p.Height == 0 && p.FPS == 0
+	return p.Width == 0 && p.Height == 0 && p.FrameInterval().IsZero()
 }
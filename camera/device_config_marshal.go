@@ -0,0 +1,188 @@
+package camera
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseFourcc 解析FOURCC（必须为4个可打印ASCII字符）
+//
+//	@param	s	FOURCC字符串（如“MJPG”）
+//	@return	FOURCC
+//	@return	异常信息
+func ParseFourcc(s string) (Fourcc, error) {
+	res := Fourcc(s)
+	if err := res.Validate(); err != nil {
+		return "", err
+	}
+	return res, nil
+}
+
+// Validate 检查FOURCC是否有效（必须为4个可打印ASCII字符）
+func (p Fourcc) Validate() error {
+	if len(p) != 4 {
+		return errors.Join(ErrInvalidParam, fmt.Errorf("fourcc %q must be 4 bytes", string(p)))
+	}
+	for i := 0; i < len(p); i++ {
+		if p[i] < 0x20 || p[i] > 0x7e {
+			return errors.Join(ErrInvalidParam, fmt.Errorf("fourcc %q contains non-printable byte 0x%02x", string(p), p[i]))
+		}
+	}
+	return nil
+}
+
+// MarshalText 实现encoding.TextMarshaler接口（不做校验，未设置的FOURCC输出为空字符串）
+func (p Fourcc) MarshalText() ([]byte, error) {
+	return []byte(p), nil
+}
+
+// UnmarshalText 实现encoding.TextUnmarshaler接口（空字符串还原为未设置的FOURCC）
+func (p *Fourcc) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*p = ""
+		return nil
+	}
+	res, err := ParseFourcc(string(text))
+	if err != nil {
+		return err
+	}
+	*p = res
+	return nil
+}
+
+// 解析配置字符串中的格式名（不足4字符时以空格补齐，如“Y16”）
+func parseFourccName(name string) (Fourcc, error) {
+	if len(name) > 0 && len(name) < 4 {
+		name += strings.Repeat(" ", 4-len(name))
+	}
+	return ParseFourcc(name)
+}
+
+// String 转换为“MJPG 1920x1080@30”格式的字符串（格式名末尾的空格会被去除，分数帧率写作“29.97”或“30000/1001”）
+func (p DeviceConfig) String() string {
+	return fmt.Sprintf("%s %dx%d@%s", strings.TrimRight(string(p.Format), " "), p.Width, p.Height, p.FrameInterval().RateString())
+}
+
+// ParseDeviceConfig 解析“MJPG 1920x1080@30”格式的配置字符串（帧率支持“29.97”、“7.5”、“30000/1001”等写法）
+//
+//	@param	s	配置字符串
+//	@return	配置信息
+//	@return	异常信息
+func ParseDeviceConfig(s string) (DeviceConfig, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return DeviceConfig{}, errors.Join(ErrInvalidParam, fmt.Errorf("invalid device config %q, want like \"MJPG 1920x1080@30\"", s))
+	}
+	format, err := parseFourccName(fields[0])
+	if err != nil {
+		return DeviceConfig{}, err
+	}
+	resolution, rate, ok := strings.Cut(fields[1], "@")
+	if !ok {
+		return DeviceConfig{}, errors.Join(ErrInvalidParam, fmt.Errorf("device config %q has no frame rate", s))
+	}
+	w, h, err := parseQueryResolution(resolution)
+	if err != nil {
+		return DeviceConfig{}, errors.Join(ErrInvalidParam, err)
+	}
+	interval, err := ParseFrameRate(rate)
+	if err != nil {
+		return DeviceConfig{}, err
+	}
+	return NewDeviceConfigWithInterval(w, h, interval, format), nil
+}
+
+// 配置信息的JSON/YAML字段
+type deviceConfigFields struct {
+	Width    uint32         `json:"width" yaml:"width"`
+	Height   uint32         `json:"height" yaml:"height"`
+	Interval *FrameInterval `json:"interval,omitempty" yaml:"interval,omitempty"` // 帧间隔（如“1001/30000”）
+	FPS      uint32         `json:"fps,omitempty" yaml:"fps,omitempty"`           // 整数帧率（仅用于读取未携带帧间隔的旧配置）
+	Format   Fourcc         `json:"format" yaml:"format"`
+}
+
+// 转换为JSON/YAML字段
+func (p DeviceConfig) fields() deviceConfigFields {
+	interval := p.FrameInterval()
+	return deviceConfigFields{Width: p.Width, Height: p.Height, Interval: &interval, Format: p.Format}
+}
+
+// 从JSON/YAML字段还原（与ParseDeviceConfig一样要求格式、宽高与帧率有效）
+func (p *DeviceConfig) setFields(v *deviceConfigFields) error {
+	if err := v.Format.Validate(); err != nil {
+		return err
+	}
+	if v.Width == 0 || v.Height == 0 {
+		return errors.Join(ErrInvalidParam, fmt.Errorf("invalid device config resolution %dx%d", v.Width, v.Height))
+	}
+	interval := FrameIntervalFromFPS(v.FPS)
+	if v.Interval != nil {
+		interval = *v.Interval
+	}
+	if interval.IsZero() {
+		return errors.Join(ErrInvalidParam, errors.New("device config has no frame rate"))
+	}
+	*p = NewDeviceConfigWithInterval(v.Width, v.Height, interval, v.Format)
+	return nil
+}
+
+// MarshalJSON 实现json.Marshaler接口（未设置的配置输出为null）
+func (p DeviceConfig) MarshalJSON() ([]byte, error) {
+	if p == (DeviceConfig{}) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(p.fields())
+}
+
+// UnmarshalJSON 实现json.Unmarshaler接口（同时支持对象与“MJPG 1920x1080@30”格式的字符串，null保持原值不变）
+func (p *DeviceConfig) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		res, err := ParseDeviceConfig(s)
+		if err != nil {
+			return err
+		}
+		*p = res
+		return nil
+	}
+	var v deviceConfigFields
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return p.setFields(&v)
+}
+
+// MarshalYAML 实现yaml.Marshaler接口（未设置的配置输出为null）
+func (p DeviceConfig) MarshalYAML() (interface{}, error) {
+	if p == (DeviceConfig{}) {
+		return nil, nil
+	}
+	return p.fields(), nil
+}
+
+// UnmarshalYAML 实现yaml.Unmarshaler接口（同时支持映射与“MJPG 1920x1080@30”格式的字符串，null保持原值不变）
+func (p *DeviceConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode && value.ShortTag() == "!!null" {
+		return nil
+	}
+	if value.Kind == yaml.ScalarNode {
+		res, err := ParseDeviceConfig(value.Value)
+		if err != nil {
+			return err
+		}
+		*p = res
+		return nil
+	}
+	var v deviceConfigFields
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+	return p.setFields(&v)
+}
@@ -0,0 +1,252 @@
+package camera
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DeviceIdentity 相机的持久化标识属性（不随/dev/videoN编号变化）
+type DeviceIdentity struct {
+	VendorID     string // USB厂商ID（4位小写十六进制）
+	ProductID    string // USB产品ID（4位小写十六进制）
+	Serial       string // USB序列号
+	Index        int    // 同一USB接口下的节点序号（UVC元数据节点通常为1）
+	ByID         string // /dev/v4l/by-id下的链接名称
+	PortPath     string // 物理端口路径（/dev/v4l/by-path下的链接名称或sysfs中的USB端口）
+	InstancePath string // Windows设备实例路径（如USB\VID_046D&PID_0825&MI_00\6&2F6BB8B2&0&0000）
+}
+
+// IsZero 是否没有任何持久化标识属性
+func (p *DeviceIdentity) IsZero() bool {
+	return *p == DeviceIdentity{}
+}
+
+// VIDPID USB厂商ID与产品ID（如"046d:0825"，未知时为空字符串）
+func (p *DeviceIdentity) VIDPID() string {
+	if p.VendorID == "" || p.ProductID == "" {
+		return ""
+	}
+	return p.VendorID + ":" + p.ProductID
+}
+
+// 部分廉价相机出厂写入的固定序列号
+var genericDeviceSerials = map[string]bool{
+	"200901010001": true,
+	"SN0001":       true,
+	"01.00.00":     true,
+}
+
+// IsGenericSerial 序列号是否疑似多台设备共用（过短、单一字符重复、顺序字符、较小的数字或已知的固定序列号）
+//
+// 此类序列号无法区分同型号的多台相机，计算ID时会改用端口路径，
+// 使相机ID只取决于自身属性，不会因接入同型号的相机而改变
+//
+//	@param	serial	USB序列号
+//	@return	是否疑似共用
+func IsGenericSerial(serial string) bool {
+	serial = strings.TrimSpace(serial)
+	if len(serial) < 4 || genericDeviceSerials[strings.ToUpper(serial)] {
+		return true
+	}
+	same, sequential, digits := true, true, true
+	for i := 0; i < len(serial); i++ {
+		if i > 0 {
+			same = same && serial[i] == serial[0]
+			sequential = sequential && serial[i] == serial[i-1]+1
+		}
+		digits = digits && serial[i] >= '0' && serial[i] <= '9'
+	}
+	return same || sequential || (digits && len(strings.TrimLeft(serial, "0")) <= 2)
+}
+
+// 计算ID使用的键（按持久程度依次选择）
+//
+//	@param	withPort	是否强制包含端口（用于区分序列号相同的设备）
+func (p *DeviceIdentity) key(withPort bool) string {
+	index := "#" + strconv.Itoa(p.Index)
+	switch {
+	case p.Serial != "" && p.VIDPID() != "" && !withPort && !IsGenericSerial(p.Serial):
+		// 同一台相机换端口后ID不变
+		return "usb:" + p.VIDPID() + ":" + p.Serial + index
+	case p.PortPath != "":
+		return "port:" + p.VIDPID() + ":" + p.PortPath + index
+	case p.InstancePath != "":
+		return "instance:" + strings.ToUpper(p.InstancePath)
+	case p.ByID != "":
+		return "by-id:" + p.ByID
+	}
+	return ""
+}
+
+// StableID 根据持久化标识属性计算相机ID
+//
+//	@return	相机ID（没有持久化标识属性时返回空字符串）
+func (p *DeviceIdentity) StableID() string {
+	return hashDeviceID(p.key(false))
+}
+
+// 计算区分端口的相机ID（序列号重复时使用）
+func (p *DeviceIdentity) portID() string {
+	return hashDeviceID(p.key(true))
+}
+
+// 对键计算md5，保持与旧版ID相同的格式
+func hashDeviceID(key string) string {
+	if key == "" {
+		return ""
+	}
+	sum := md5.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// LegacyDeviceID 旧版相机ID（md5(devicePath + name)，会随/dev/videoN编号变化）
+//
+//	@param	devicePath	相机系统路径
+//	@param	name		相机名称
+//	@return	旧版相机ID
+func LegacyDeviceID(devicePath, name string) string {
+	return hashDeviceID(devicePath + name)
+}
+
+// ResolveDeviceIdentity 解析相机系统路径对应的持久化标识属性
+//
+// Windows下从设备符号链接中解析设备实例路径，Linux下从root中的/dev/v4l与/sys/class/video4linux读取
+//
+//	@param	root		文件系统根目录（通常为"/"，测试时可指向伪造的目录树）
+//	@param	devicePath	相机系统路径（如/dev/video0或\\?\usb#vid_046d&pid_0825&mi_00#...）
+//	@return	持久化标识属性
+func ResolveDeviceIdentity(root, devicePath string) DeviceIdentity {
+	if instance := ParseWindowsInstancePath(devicePath); instance != "" {
+		res := DeviceIdentity{InstancePath: instance}
+		for _, part := range strings.Split(strings.ToLower(instance), "&") {
+			part = part[strings.LastIndex(part, `\`)+1:]
+			if v, ok := strings.CutPrefix(part, "vid_"); ok {
+				res.VendorID = v
+			} else if v, ok := strings.CutPrefix(part, "pid_"); ok {
+				res.ProductID = v
+			}
+		}
+		return res
+	}
+	if !strings.HasPrefix(devicePath, "/dev/") {
+		return DeviceIdentity{}
+	}
+
+	res := DeviceIdentity{
+		ByID:     findDeviceLink(root, "dev/v4l/by-id", devicePath),
+		PortPath: findDeviceLink(root, "dev/v4l/by-path", devicePath),
+	}
+
+	// 读取sysfs
+	node := filepath.Join(root, "sys/class/video4linux", filepath.Base(devicePath))
+	if index, err := strconv.Atoi(readSysfsAttr(node, "index")); err == nil {
+		res.Index = index
+	}
+	if usb := findUSBDevice(root, node); usb != "" {
+		res.VendorID = strings.ToLower(readSysfsAttr(usb, "idVendor"))
+		res.ProductID = strings.ToLower(readSysfsAttr(usb, "idProduct"))
+		res.Serial = readSysfsAttr(usb, "serial")
+		if res.PortPath == "" {
+			res.PortPath = "usb-" + filepath.Base(usb)
+		}
+	}
+	return res
+}
+
+// ParseWindowsInstancePath 从Windows设备符号链接中解析设备实例路径
+//
+//	@param	symbolicLink	设备符号链接（如\\?\usb#vid_046d&pid_0825&mi_00#6&2f6bb8b2&0&0000#{e5323777-f976-4f5b-9b55-b94699c46e44}\global）
+//	@return	设备实例路径（如USB\VID_046D&PID_0825&MI_00\6&2F6BB8B2&0&0000，无法解析时返回空字符串）
+func ParseWindowsInstancePath(symbolicLink string) string {
+	rest, ok := strings.CutPrefix(symbolicLink, `\\?\`)
+	if !ok {
+		return ""
+	}
+	parts := strings.Split(rest, "#")
+	if len(parts) < 3 {
+		return ""
+	}
+	return strings.ToUpper(strings.Join(parts[:3], `\`))
+}
+
+// 在链接目录中查找指向设备节点的链接名称
+func findDeviceLink(root, dir, devicePath string) string {
+	target, err := filepath.EvalSymlinks(filepath.Join(root, devicePath))
+	if err != nil {
+		return ""
+	}
+	entries, err := os.ReadDir(filepath.Join(root, dir))
+	if err != nil {
+		return ""
+	}
+	for _, v := range entries {
+		if res, err := filepath.EvalSymlinks(filepath.Join(root, dir, v.Name())); err == nil && res == target {
+			return v.Name()
+		}
+	}
+	return ""
+}
+
+// 从video4linux节点向上查找所属的USB设备目录（包含idVendor的目录）
+func findUSBDevice(root, node string) string {
+	dir, err := filepath.EvalSymlinks(filepath.Join(node, "device"))
+	if err != nil {
+		return ""
+	}
+	root, err = filepath.EvalSymlinks(root)
+	if err != nil {
+		return ""
+	}
+	for strings.HasPrefix(dir, root) && dir != root {
+		if _, err := os.Stat(filepath.Join(dir, "idVendor")); err == nil {
+			return dir
+		}
+		dir = filepath.Dir(dir)
+	}
+	return ""
+}
+
+// 读取sysfs属性（去除首尾空白，读取失败时返回空字符串）
+func readSysfsAttr(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// AssignDeviceID 为相机分配ID：优先使用持久化标识属性计算的ID，无法获取时回退到旧版ID
+//
+//	@param	device		相机信息（需已填写Name、SymbolicLink与Identity）
+func AssignDeviceID(device *Device) {
+	device.LegacyID = LegacyDeviceID(device.SymbolicLink, device.Name)
+	device.ID = device.Identity.StableID()
+	if device.ID == "" {
+		device.ID = device.LegacyID
+	}
+}
+
+// DedupeIDs 处理仍然重复的ID：改用包含端口的ID，仍然重复时使用旧版ID
+//
+// 常见的固定序列号已由IsGenericSerial识别并直接使用端口路径，不会走到这里；
+// 仅当未识别的序列号被多台设备共用时，这些设备的ID才会改变
+func (s DeviceList) DedupeIDs() {
+	for _, fallback := range []func(*Device) string{
+		func(d *Device) string { return d.Identity.portID() },
+		func(d *Device) string { return d.LegacyID },
+	} {
+		count := map[string]int{}
+		for _, v := range s {
+			count[v.ID]++
+		}
+		for _, v := range s {
+			if id := fallback(v); count[v.ID] > 1 && id != "" {
+				v.ID = id
+			}
+		}
+	}
+}
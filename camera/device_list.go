@@ -15,7 +15,7 @@ func (s DeviceList) Clone() DeviceList {
 	return res
 }
 
-// Get 在列表中查询相机设备信息
+// Get 在列表中查询相机设备信息（兼容旧版相机ID）
 func (s DeviceList) Get(id string) (*Device, error) {
 	// 遍历全部相机信息
 	for _, item := range s {
@@ -26,6 +26,13 @@ func (s DeviceList) Get(id string) (*Device, error) {
 		return item.Clone(), nil
 	}
 
+	// 兼容保存的旧版相机ID
+	for _, item := range s {
+		if id != "" && item.LegacyID == id {
+			return item.Clone(), nil
+		}
+	}
+
 	// 默认为找不到匹配的相机
 	return nil, ErrDeviceNotFound
 }
@@ -0,0 +1,167 @@
+package camera
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DeviceCaps 设备能力标志（与V4L2的device_caps一致）
+type DeviceCaps uint32
+
+const (
+	CapVideoCapture       DeviceCaps = 0x00000001 // 视频采集
+	CapVideoOutput        DeviceCaps = 0x00000002 // 视频输出
+	CapVideoCaptureMplane DeviceCaps = 0x00001000 // 多平面视频采集
+	CapVideoOutputMplane  DeviceCaps = 0x00002000 // 多平面视频输出
+	CapVideoM2MMplane     DeviceCaps = 0x00004000 // 多平面内存到内存（编解码器等）
+	CapVideoM2M           DeviceCaps = 0x00008000 // 内存到内存（编解码器等）
+	CapMetaCapture        DeviceCaps = 0x00800000 // 元数据采集（如UVC元数据节点）
+	CapStreaming          DeviceCaps = 0x04000000 // 支持流式I/O
+	CapMetaOutput         DeviceCaps = 0x08000000 // 元数据输出
+)
+
+// 能力标志名称
+var deviceCapsNames = []struct {
+	cap  DeviceCaps
+	name string
+}{
+	{CapVideoCapture, "video-capture"},
+	{CapVideoOutput, "video-output"},
+	{CapVideoCaptureMplane, "video-capture-mplane"},
+	{CapVideoOutputMplane, "video-output-mplane"},
+	{CapVideoM2MMplane, "video-m2m-mplane"},
+	{CapVideoM2M, "video-m2m"},
+	{CapMetaCapture, "meta-capture"},
+	{CapStreaming, "streaming"},
+	{CapMetaOutput, "meta-output"},
+}
+
+// Has 是否包含全部指定能力
+func (c DeviceCaps) Has(caps DeviceCaps) bool {
+	return c&caps == caps
+}
+
+// IsCapture 是否为视频采集节点
+func (c DeviceCaps) IsCapture() bool {
+	return c&(CapVideoCapture|CapVideoCaptureMplane) != 0
+}
+
+// IsMetadata 是否为元数据节点
+func (c DeviceCaps) IsMetadata() bool {
+	return c&(CapMetaCapture|CapMetaOutput) != 0
+}
+
+// String 能力标志名称（以"|"分隔）
+func (c DeviceCaps) String() string {
+	var names []string
+	rest := c
+	for _, v := range deviceCapsNames {
+		if c.Has(v.cap) {
+			names = append(names, v.name)
+			rest &^= v.cap
+		}
+	}
+	if rest != 0 {
+		names = append(names, fmt.Sprintf("0x%x", uint32(rest)))
+	}
+	return strings.Join(names, "|")
+}
+
+// DeviceMetadata 相机硬件信息
+type DeviceMetadata struct {
+	Driver       string     // 驱动名称（如uvcvideo）
+	BusInfo      string     // 总线信息（如usb-0000:00:14.0-2）
+	Manufacturer string     // 制造商
+	Product      string     // 产品名称
+	Firmware     string     // 固件版本（USB bcdDevice，如"1.00"）
+	Caps         DeviceCaps // 节点能力标志
+}
+
+// USB根集线器目录名称（如usb1）
+var usbRootHubRegexp = regexp.MustCompile(`^usb\d+$`)
+
+// ResolveDeviceMetadata 读取相机硬件信息
+//
+// Linux下从root中的/sys/class/video4linux读取，能力标志优先通过VIDIOC_QUERYCAP获取，
+// 无法查询时根据驱动与节点序号推断；Windows下的设备均视为视频采集节点
+//
+//	@param	root		文件系统根目录（通常为"/"，测试时可指向伪造的目录树）
+//	@param	devicePath	相机系统路径
+//	@return	相机硬件信息
+func ResolveDeviceMetadata(root, devicePath string) DeviceMetadata {
+	if ParseWindowsInstancePath(devicePath) != "" {
+		return DeviceMetadata{Caps: CapVideoCapture | CapStreaming}
+	}
+	if !strings.HasPrefix(devicePath, "/dev/") {
+		return DeviceMetadata{}
+	}
+
+	res := DeviceMetadata{}
+	node := filepath.Join(root, "sys/class/video4linux", filepath.Base(devicePath))
+	if driver, err := filepath.EvalSymlinks(filepath.Join(node, "device/driver")); err == nil {
+		res.Driver = filepath.Base(driver)
+	}
+	if usb := findUSBDevice(root, node); usb != "" {
+		res.Manufacturer = readSysfsAttr(usb, "manufacturer")
+		res.Product = readSysfsAttr(usb, "product")
+		res.Firmware = formatBCD(readSysfsAttr(usb, "bcdDevice"))
+		res.BusInfo = usbBusInfo(usb)
+	}
+
+	// 能力标志
+	if info, ok := queryDeviceCaps(filepath.Join(root, devicePath)); ok {
+		res.Caps = info.caps
+		if res.Driver == "" {
+			res.Driver = info.driver
+		}
+		if info.busInfo != "" {
+			res.BusInfo = info.busInfo
+		}
+		return res
+	}
+	index, _ := strconv.Atoi(readSysfsAttr(node, "index"))
+	if res.Driver == "uvcvideo" && index > 0 {
+		// UVC驱动在4.16内核之后为每个采集节点额外创建一个元数据节点
+		res.Caps = CapMetaCapture | CapStreaming
+	} else {
+		res.Caps = CapVideoCapture | CapStreaming
+	}
+	return res
+}
+
+// 通过VIDIOC_QUERYCAP获取的信息
+type deviceCapsInfo struct {
+	driver  string
+	busInfo string
+	caps    DeviceCaps
+}
+
+// 按UVC驱动的格式生成总线信息（usb-<控制器>-<端口>）
+func usbBusInfo(usb string) string {
+	base := filepath.Base(usb)
+	_, port, ok := strings.Cut(base, "-")
+	if !ok {
+		return ""
+	}
+	for dir := filepath.Dir(usb); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if usbRootHubRegexp.MatchString(filepath.Base(dir)) {
+			return "usb-" + filepath.Base(filepath.Dir(dir)) + "-" + port
+		}
+	}
+	return ""
+}
+
+// 格式化BCD版本号（如"0102"转换为"1.02"）
+func formatBCD(bcd string) string {
+	if len(bcd) != 4 {
+		return bcd
+	}
+	major, err := strconv.Atoi(bcd[:2])
+	if err != nil {
+		return bcd
+	}
+	return strconv.Itoa(major) + "." + bcd[2:]
+}
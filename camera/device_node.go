@@ -0,0 +1,75 @@
+package camera
+
+import "strings"
+
+// DeviceNodeType 设备节点类型（可按位组合用于筛选）
+type DeviceNodeType uint32
+
+const (
+	NodeCapture  DeviceNodeType = 1 << iota // 视频采集节点
+	NodeMetadata                            // 元数据节点（如UVC相机的第二个/dev/videoN）
+	NodeOutput                              // 视频输出节点
+	NodeM2M                                 // 内存到内存节点（编解码器、缩放器等）
+
+	// NodeAll 所有节点类型
+	NodeAll = NodeCapture | NodeMetadata | NodeOutput | NodeM2M
+)
+
+// 节点类型名称
+var deviceNodeTypeNames = []struct {
+	typ  DeviceNodeType
+	name string
+}{
+	{NodeCapture, "capture"},
+	{NodeMetadata, "metadata"},
+	{NodeOutput, "output"},
+	{NodeM2M, "m2m"},
+}
+
+// String 节点类型名称（多个类型以"|"分隔）
+func (t DeviceNodeType) String() string {
+	var names []string
+	for _, v := range deviceNodeTypeNames {
+		if t&v.typ != 0 {
+			names = append(names, v.name)
+		}
+	}
+	if len(names) == 0 {
+		return "unknown"
+	}
+	return strings.Join(names, "|")
+}
+
+// DeviceNodeTypeFromCaps 根据能力标志判断节点类型（能力标志未知时视为采集节点）
+//
+//	@param	caps	能力标志
+//	@return	节点类型
+func DeviceNodeTypeFromCaps(caps DeviceCaps) DeviceNodeType {
+	switch {
+	case caps&(CapVideoM2M|CapVideoM2MMplane) != 0,
+		caps.IsCapture() && caps&(CapVideoOutput|CapVideoOutputMplane) != 0:
+		return NodeM2M
+	case caps.IsCapture():
+		return NodeCapture
+	case caps.IsMetadata():
+		return NodeMetadata
+	case caps&(CapVideoOutput|CapVideoOutputMplane) != 0:
+		return NodeOutput
+	default:
+		return NodeCapture
+	}
+}
+
+// FilterNodeTypes 筛选指定类型的节点
+//
+//	@param	types	节点类型（可按位组合）
+//	@return	筛选后的相机列表
+func (s DeviceList) FilterNodeTypes(types DeviceNodeType) DeviceList {
+	var res DeviceList
+	for _, v := range s {
+		if v.NodeType&types != 0 {
+			res = append(res, v)
+		}
+	}
+	return res
+}
@@ -0,0 +1,183 @@
+package camera
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultWatchInterval 无法监听系统事件时的轮询间隔
+	DefaultWatchInterval = time.Second
+	// 收到系统事件后等待udev创建链接与设置权限的时间
+	watchDebounce = 300 * time.Millisecond
+)
+
+// DeviceEventType 相机事件类型
+type DeviceEventType int
+
+const (
+	DeviceAdded   DeviceEventType = iota // 相机接入
+	DeviceRemoved                        // 相机移除
+	DeviceChanged                        // 相机信息变化（如重新接入后节点编号改变）
+)
+
+// String 事件类型名称
+func (t DeviceEventType) String() string {
+	switch t {
+	case DeviceAdded:
+		return "added"
+	case DeviceRemoved:
+		return "removed"
+	case DeviceChanged:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// DeviceEvent 相机事件
+type DeviceEvent struct {
+	Type   DeviceEventType // 事件类型
+	Device *Device         // 相机信息（移除事件为移除前的信息）
+}
+
+// DiffDeviceList 对比新旧相机列表（按相机ID匹配）
+//
+//	@param	old	旧列表
+//	@param	new	新列表
+//	@return	相机事件（先移除，再接入与变化）
+func DiffDeviceList(old, new DeviceList) []DeviceEvent {
+	var res []DeviceEvent
+	newMap := make(map[string]*Device, len(new))
+	for _, v := range new {
+		newMap[v.ID] = v
+	}
+	oldMap := make(map[string]*Device, len(old))
+	for _, v := range old {
+		oldMap[v.ID] = v
+		if _, ok := newMap[v.ID]; !ok {
+			res = append(res, DeviceEvent{Type: DeviceRemoved, Device: v.Clone()})
+		}
+	}
+	for _, v := range new {
+		prev, ok := oldMap[v.ID]
+		if !ok {
+			res = append(res, DeviceEvent{Type: DeviceAdded, Device: v.Clone()})
+		} else if *prev != *v {
+			res = append(res, DeviceEvent{Type: DeviceChanged, Device: v.Clone()})
+		}
+	}
+	return res
+}
+
+// DeviceWatcher 相机热插拔监听器
+type DeviceWatcher struct {
+	List     func() (DeviceList, error) // 枚举相机列表
+	Interval time.Duration              // 轮询间隔（为0时使用DefaultWatchInterval）
+	Poll     bool                       // 强制使用轮询（默认优先监听系统事件，Linux下为/dev的inotify）
+	Notify   <-chan struct{}            // 自定义系统事件来源（如应用已有的udev监听，设置后不再使用内置的监听）
+}
+
+// Watch 开始监听相机接入与移除，仅在系统事件触发或轮询时重新枚举
+//
+//	@param	ctx	上下文（取消后停止监听并关闭事件通道）
+//	@return	事件通道
+//	@return	异常信息（首次枚举失败时返回）
+func (p *DeviceWatcher) Watch(ctx context.Context) (<-chan DeviceEvent, error) {
+	if p.List == nil {
+		return nil, ErrInvalidParam
+	}
+	prev, err := p.List()
+	if err != nil {
+		return nil, err
+	}
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	// 优先监听系统事件，失败时回退到轮询
+	notify := p.Notify
+	if notify == nil && !p.Poll {
+		notify, err = watchDeviceNodes(ctx)
+		if err != nil {
+			notify = nil
+		}
+	}
+	var ticker *time.Ticker
+	var tick <-chan time.Time
+	if notify == nil {
+		ticker = time.NewTicker(interval)
+		tick = ticker.C
+	}
+
+	events := make(chan DeviceEvent, 16)
+	var retry *time.Timer // 枚举失败后的重试（监听系统事件时不会有新的触发）
+	go func() {
+		defer close(events)
+		defer func() {
+			if ticker != nil {
+				ticker.Stop()
+			}
+			if retry != nil {
+				retry.Stop()
+			}
+		}()
+		for {
+			var retryC <-chan time.Time
+			if retry != nil {
+				retryC = retry.C
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-retryC:
+				retry = nil
+			case _, ok := <-notify:
+				if !ok {
+					// 系统事件监听中断，回退到轮询
+					notify = nil
+					ticker = time.NewTicker(interval)
+					tick = ticker.C
+					continue
+				}
+				// 合并短时间内的多个事件
+				timer := time.NewTimer(watchDebounce)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+				select {
+				case <-notify:
+				default:
+				}
+			case <-tick:
+			}
+
+			// 重新枚举并对比
+			list, err := p.List()
+			if err != nil {
+				// 轮询模式会在下次轮询时重试，监听模式需要按轮询间隔安排重试，避免丢失本次事件
+				if tick == nil && retry == nil {
+					retry = time.NewTimer(interval)
+				}
+				continue
+			}
+			if retry != nil {
+				retry.Stop()
+				retry = nil
+			}
+			for _, event := range DiffDeviceList(prev, list) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			prev = list
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,63 @@
+//go:build linux
+
+package camera
+
+import (
+	"context"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// 监听/dev下video节点的创建、删除与权限变化
+//
+//	@param	ctx	上下文（取消后停止监听并关闭通道）
+//	@return	事件通知通道（多个事件会被合并）
+//	@return	异常信息
+func watchDeviceNodes(ctx context.Context) (<-chan struct{}, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := syscall.InotifyAddWatch(fd, "/dev", syscall.IN_CREATE|syscall.IN_DELETE|syscall.IN_ATTRIB); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	// 非阻塞描述符交由运行时轮询，关闭文件即可中断读取
+	file := os.NewFile(uintptr(fd), "inotify")
+
+	notify := make(chan struct{}, 1)
+	go func() {
+		<-ctx.Done()
+		file.Close()
+	}()
+	go func() {
+		defer close(notify)
+		buf := make([]byte, 4096)
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				return
+			}
+			for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+				event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+				nameStart := offset + syscall.SizeofInotifyEvent
+				nameEnd := nameStart + int(event.Len)
+				offset = nameEnd
+				if nameEnd > n {
+					break
+				}
+				name := strings.TrimRight(string(buf[nameStart:nameEnd]), "\x00")
+				if !strings.HasPrefix(name, "video") {
+					continue
+				}
+				select {
+				case notify <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return notify, nil
+}
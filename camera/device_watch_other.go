@@ -0,0 +1,13 @@
+//go:build !linux
+
+package camera
+
+import (
+	"context"
+	"errors"
+)
+
+// 非Linux平台暂不支持系统事件监听，使用轮询
+func watchDeviceNodes(ctx context.Context) (<-chan struct{}, error) {
+	return nil, errors.New("device node watch is not supported on this platform")
+}
@@ -16,6 +16,15 @@ const (
 	ErrGetFrameFailed             // 获取帧失败
 	ErrDecodeJpegImageFailed      // 解码JPEG图像失败
 	ErrDeviceNotOpen              // 设备未打开
+	ErrUnsupportedFrameFormat     // 不支持的帧格式
+	ErrFrameSizeMismatch          // 帧数据长度与配置不匹配
+	ErrInvalidParam               // 参数错误
+	ErrConvertPathNotFound        // 未找到帧格式转换路径
+	ErrEncodeJpegImageFailed      // 编码JPEG图像失败
+	ErrFrameCorrupted             // 帧数据已损坏
+	ErrParseBitstreamFailed       // 解析码流失败
+	ErrFrameTimeout               // 等待帧超时
+	ErrDeviceAliasAmbiguous       // 设备别名匹配到多个设备
 )
 
 // 错误码变量名映射
@@ -29,4 +38,13 @@ var errVarName = map[errno]string{
 	ErrGetFrameFailed:             "ErrGetFrameFailed",
 	ErrDecodeJpegImageFailed:      "ErrDecodeJpegImageFailed",
 	ErrDeviceNotOpen:              "ErrDeviceNotOpen",
+	ErrUnsupportedFrameFormat:     "ErrUnsupportedFrameFormat",
+	ErrFrameSizeMismatch:          "ErrFrameSizeMismatch",
+	ErrInvalidParam:               "ErrInvalidParam",
+	ErrConvertPathNotFound:        "ErrConvertPathNotFound",
+	ErrEncodeJpegImageFailed:      "ErrEncodeJpegImageFailed",
+	ErrFrameCorrupted:             "ErrFrameCorrupted",
+	ErrParseBitstreamFailed:       "ErrParseBitstreamFailed",
+	ErrFrameTimeout:               "ErrFrameTimeout",
+	ErrDeviceAliasAmbiguous:       "ErrDeviceAliasAmbiguous",
 }
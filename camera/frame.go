@@ -0,0 +1,48 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Frame 视频帧
+type Frame struct {
+	Data        []byte         // 帧数据
+	Config      DeviceConfig   // 帧信息
+	Orientation RGBOrientation // RGB帧的行顺序（零值表示按采集后端自动判断）
+}
+
+// Clone 克隆视频帧
+func (p *Frame) Clone() *Frame {
+	if p == nil {
+		return nil
+	}
+	return &Frame{
+		Data:        append([]byte(nil), p.Data...),
+		Config:      p.Config,
+		Orientation: p.Orientation,
+	}
+}
+
+// 检查帧信息并计算帧数据的行跨度（兼容带行尾填充的帧）
+//
+//	@param	data		帧数据
+//	@param	info		帧信息
+//	@param	minStride	每行最少字节数
+//	@return	行跨度
+//	@return	异常信息
+func frameStride(data []byte, info *DeviceConfig, minStride int) (int, error) {
+	// 帧信息是否有效
+	if info == nil || info.Width == 0 || info.Height == 0 {
+		return 0, errors.Join(ErrFrameSizeMismatch, errors.New("empty frame size"))
+	}
+
+	// 按帧高度平均分配行跨度
+	stride := len(data) / int(info.Height)
+	if stride < minStride {
+		return 0, errors.Join(ErrFrameSizeMismatch, fmt.Errorf("need %d bytes, got %d", minStride*int(info.Height), len(data)))
+	}
+
+	// OK
+	return stride, nil
+}
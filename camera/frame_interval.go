@@ -0,0 +1,183 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FrameInterval 帧间隔（以秒为单位的分数，如NTSC 29.97帧为1001/30000秒）
+type FrameInterval struct {
+	Numerator   uint32 // 分子
+	Denominator uint32 // 分母
+}
+
+// 最大公约数
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// NewFrameInterval 创建帧间隔（自动约分，分子或分母为0时返回零值）
+//
+//	@param	numerator	分子
+//	@param	denominator	分母
+//	@return	帧间隔
+func NewFrameInterval(numerator, denominator uint32) FrameInterval {
+	if numerator == 0 || denominator == 0 {
+		return FrameInterval{}
+	}
+	g := uint32(gcd(uint64(numerator), uint64(denominator)))
+	return FrameInterval{Numerator: numerator / g, Denominator: denominator / g}
+}
+
+// FrameIntervalFromFPS 由整数帧率创建帧间隔
+//
+//	@param	fps	帧率
+//	@return	帧间隔
+func FrameIntervalFromFPS(fps uint32) FrameInterval {
+	return NewFrameInterval(1, fps)
+}
+
+// ParseFrameInterval 解析“1001/30000”格式的帧间隔
+//
+//	@param	s	帧间隔字符串
+//	@return	帧间隔
+//	@return	异常信息
+func ParseFrameInterval(s string) (FrameInterval, error) {
+	num, den, ok := strings.Cut(s, "/")
+	n, err1 := strconv.ParseUint(num, 10, 32)
+	d, err2 := strconv.ParseUint(den, 10, 32)
+	if !ok || err1 != nil || err2 != nil || n == 0 || d == 0 {
+		return FrameInterval{}, errors.Join(ErrInvalidParam, fmt.Errorf("invalid frame interval %q", s))
+	}
+	return NewFrameInterval(uint32(n), uint32(d)), nil
+}
+
+// ParseFrameRate 解析帧率（支持“30”、“7.5”、“30000/1001”，以及按NTSC识别的“29.97”、“59.94”、“23.976”）
+//
+//	@param	s	帧率字符串
+//	@return	帧间隔
+//	@return	异常信息
+func ParseFrameRate(s string) (FrameInterval, error) {
+	// 分数形式
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		interval, err := ParseFrameInterval(den + "/" + num)
+		if err != nil {
+			return FrameInterval{}, errors.Join(ErrInvalidParam, fmt.Errorf("invalid frame rate %q", s))
+		}
+		return interval, nil
+	}
+
+	// 小数形式
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil || !(rate > 0) || rate > math.MaxUint32 {
+		return FrameInterval{}, errors.Join(ErrInvalidParam, fmt.Errorf("invalid frame rate %q", s))
+	}
+	// NTSC帧率（N*1000/1001）
+	if n := math.Round(rate * 1.001); n >= 1 && n*1000 <= math.MaxUint32 &&
+		math.Abs(rate-n*1000/1001) < 0.005 && math.Abs(rate-n) >= 0.005 {
+		return NewFrameInterval(1001, uint32(n*1000)), nil
+	}
+	// 十进制小数（最多保留6位）
+	scale := 1.0
+	for scale < 1e6 && math.Abs(rate*scale-math.Round(rate*scale)) > 1e-6 {
+		scale *= 10
+	}
+	if rate*scale > math.MaxUint32 {
+		return FrameInterval{}, errors.Join(ErrInvalidParam, fmt.Errorf("frame rate %q is too precise", s))
+	}
+	return NewFrameInterval(uint32(scale), uint32(math.Round(rate*scale))), nil
+}
+
+// IsZero 是否为零值
+func (p FrameInterval) IsZero() bool {
+	return p.Numerator == 0 || p.Denominator == 0
+}
+
+// Rate 帧率（零值时返回0）
+func (p FrameInterval) Rate() float64 {
+	if p.IsZero() {
+		return 0
+	}
+	return float64(p.Denominator) / float64(p.Numerator)
+}
+
+// FPS 四舍五入后的整数帧率
+func (p FrameInterval) FPS() uint32 {
+	if p.IsZero() {
+		return 0
+	}
+	return uint32((uint64(p.Denominator) + uint64(p.Numerator)/2) / uint64(p.Numerator))
+}
+
+// Cmp 比较两个帧间隔对应的帧率
+//
+//	@param	v	另一个帧间隔
+//	@return	帧率更低时返回-1，相等时返回0，更高时返回1
+func (p FrameInterval) Cmp(v FrameInterval) int {
+	if p.IsZero() || v.IsZero() {
+		switch {
+		case p.IsZero() && v.IsZero():
+			return 0
+		case p.IsZero():
+			return -1
+		default:
+			return 1
+		}
+	}
+	a := uint64(p.Denominator) * uint64(v.Numerator)
+	b := uint64(v.Denominator) * uint64(p.Numerator)
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// String 转换为“1001/30000”格式的字符串
+func (p FrameInterval) String() string {
+	return fmt.Sprintf("%d/%d", p.Numerator, p.Denominator)
+}
+
+// RateString 转换为帧率字符串（如“30”、“29.97”、“7.5”，最多保留3位小数）
+func (p FrameInterval) RateString() string {
+	if p.IsZero() {
+		return "0"
+	}
+	if p.Denominator%p.Numerator == 0 {
+		return strconv.FormatUint(uint64(p.Denominator/p.Numerator), 10)
+	}
+	res := strings.TrimRight(strings.TrimRight(strconv.FormatFloat(p.Rate(), 'f', 3, 64), "0"), ".")
+	// 小数无法精确还原时使用分数形式
+	if v, err := ParseFrameRate(res); err != nil || v.Cmp(p) != 0 {
+		return fmt.Sprintf("%d/%d", p.Denominator, p.Numerator)
+	}
+	return res
+}
+
+// MarshalText 实现encoding.TextMarshaler接口
+func (p FrameInterval) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText 实现encoding.TextUnmarshaler接口
+func (p *FrameInterval) UnmarshalText(text []byte) error {
+	if string(text) == (FrameInterval{}).String() {
+		*p = FrameInterval{}
+		return nil
+	}
+	res, err := ParseFrameInterval(string(text))
+	if err != nil {
+		return err
+	}
+	*p = res
+	return nil
+}
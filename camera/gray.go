@@ -0,0 +1,209 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// GrayBitDepth 获取高位深灰度格式的有效位深
+//
+//	@param	format	帧格式
+//	@return	有效位深（非高位深灰度格式返回0）
+func GrayBitDepth(format Fourcc) int {
+	switch format {
+	case FOURCC_Y10, FOURCC_Y10P, FOURCC_Y10BPACK:
+		return 10
+	case FOURCC_Y12:
+		return 12
+	case FOURCC_Y14:
+		return 14
+	case FOURCC_Y16:
+		return 16
+	default:
+		return 0
+	}
+}
+
+// 将有效位左对齐到16位，并用高位填充低位以覆盖完整的16位范围
+func expandGray16(v uint16, bits int) uint16 {
+	if bits >= 16 {
+		return v
+	}
+	return v<<(16-bits) | v>>(2*bits-16)
+}
+
+// DecodeGray16 将高位深灰度帧解包为16位灰度图像
+//
+// 支持Y10、Y12、Y14、Y16（小端序16位存储）以及Y10P（MIPI RAW10）、
+// Y10BPACK（大端序位紧凑）格式，有效位会被扩展到完整的16位范围
+//
+//	@param	data	帧数据
+//	@param	info	帧信息
+//	@return	16位灰度图像
+//	@return	异常信息
+func DecodeGray16(data []byte, info *DeviceConfig) (*image.Gray16, error) {
+	// 获取有效位深
+	if info == nil {
+		return nil, errors.Join(ErrFrameSizeMismatch, errors.New("empty frame size"))
+	}
+	bits := GrayBitDepth(info.Format)
+	if bits == 0 {
+		return nil, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q is not high bit depth grey", info.Format))
+	}
+
+	// 计算每行最少字节数
+	width := int(info.Width)
+	var minStride int
+	switch info.Format {
+	case FOURCC_Y10P:
+		// 每4个像素占用5个字节
+		minStride = (width + 3) / 4 * 5
+	case FOURCC_Y10BPACK:
+		// 每个像素占用10位
+		minStride = (width*10 + 7) / 8
+	default:
+		// 每个像素占用2个字节
+		minStride = width * 2
+	}
+	stride, err := frameStride(data, info, minStride)
+	if err != nil {
+		return nil, err
+	}
+
+	// 逐行解包
+	img := image.NewGray16(image.Rect(0, 0, width, int(info.Height)))
+	for y := 0; y < img.Rect.Dy(); y++ {
+		src := data[y*stride : y*stride+minStride]
+		dst := img.Pix[y*img.Stride : y*img.Stride+width*2]
+		switch info.Format {
+		case FOURCC_Y10P:
+			unpackY10P(dst, src, width)
+		case FOURCC_Y10BPACK:
+			unpackY10BPack(dst, src, width)
+		default:
+			unpackGray16LE(dst, src, width, bits)
+		}
+	}
+
+	// OK
+	return img, nil
+}
+
+// 解包小端序16位存储的灰度行
+func unpackGray16LE(dst, src []byte, width, bits int) {
+	mask := uint16(1<<bits - 1)
+	for x := 0; x < width; x++ {
+		v := expandGray16((uint16(src[2*x])|uint16(src[2*x+1])<<8)&mask, bits)
+		dst[2*x] = byte(v >> 8)
+		dst[2*x+1] = byte(v)
+	}
+}
+
+// 解包MIPI RAW10灰度行（前4个字节为高8位，第5个字节依次存放4个像素的低2位）
+func unpackY10P(dst, src []byte, width int) {
+	for x := 0; x < width; x++ {
+		group := src[x/4*5:]
+		i := x % 4
+		v := expandGray16(uint16(group[i])<<2|uint16(group[4]>>(2*i))&0x3, 10)
+		dst[2*x] = byte(v >> 8)
+		dst[2*x+1] = byte(v)
+	}
+}
+
+// 解包大端序位紧凑的10位灰度行
+func unpackY10BPack(dst, src []byte, width int) {
+	var acc uint32 // 位缓冲区
+	var accBits int
+	var pos int
+	for x := 0; x < width; x++ {
+		// 补足10位
+		for accBits < 10 {
+			acc = acc<<8 | uint32(src[pos])
+			pos++
+			accBits += 8
+		}
+		accBits -= 10
+		v := expandGray16(uint16(acc>>accBits)&0x3ff, 10)
+		dst[2*x] = byte(v >> 8)
+		dst[2*x+1] = byte(v)
+	}
+}
+
+// WindowLevel 窗宽窗位，用于将16位灰度映射为8位灰度进行显示
+type WindowLevel struct {
+	Window uint16 // 窗宽（映射到0~255的灰度区间宽度，0表示全范围）
+	Level  uint16 // 窗位（灰度区间的中心值）
+}
+
+// AutoWindowLevel 根据图像的最小值和最大值计算窗宽窗位
+//
+//	@param	img	16位灰度图像
+//	@return	窗宽窗位
+func AutoWindowLevel(img *image.Gray16) WindowLevel {
+	if img == nil || img.Rect.Empty() {
+		return WindowLevel{}
+	}
+
+	// 统计最小值和最大值
+	lo, hi := uint16(0xffff), uint16(0)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(img.Rect.Min.X, y):img.PixOffset(img.Rect.Max.X, y)]
+		for i := 0; i < len(row); i += 2 {
+			v := uint16(row[i])<<8 | uint16(row[i+1])
+			if v < lo {
+				lo = v
+			}
+			if v > hi {
+				hi = v
+			}
+		}
+	}
+
+	// 至少保留1的窗宽，避免除零
+	window := hi - lo
+	if window == 0 {
+		window = 1
+	}
+	return WindowLevel{
+		Window: window,
+		Level:  lo + window/2,
+	}
+}
+
+// Gray16ToGray 按窗宽窗位将16位灰度图像映射为8位灰度图像
+//
+//	@param	img	16位灰度图像
+//	@param	wl	窗宽窗位（窗宽为0时使用全范围线性映射）
+//	@return	8位灰度图像
+func Gray16ToGray(img *image.Gray16, wl WindowLevel) *image.Gray {
+	if img == nil {
+		return nil
+	}
+
+	// 计算窗口下限和宽度
+	lower, window := 0, 0x10000
+	if wl.Window > 0 {
+		lower = int(wl.Level) - int(wl.Window)/2
+		window = int(wl.Window)
+	}
+
+	// 逐像素映射
+	res := image.NewGray(img.Rect)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		src := img.Pix[img.PixOffset(img.Rect.Min.X, y):]
+		dst := res.Pix[res.PixOffset(res.Rect.Min.X, y):]
+		for x := 0; x < img.Rect.Dx(); x++ {
+			v := (int(uint16(src[2*x])<<8|uint16(src[2*x+1])) - lower) * 256 / window
+			if v < 0 {
+				v = 0
+			} else if v > 0xff {
+				v = 0xff
+			}
+			dst[x] = uint8(v)
+		}
+	}
+
+	// OK
+	return res
+}
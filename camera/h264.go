@@ -0,0 +1,331 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// H264NALType H.264 NAL单元类型
+type H264NALType uint8
+
+const (
+	H264NALSlice       H264NALType = 1  // 非IDR图像的片
+	H264NALSliceA      H264NALType = 2  // 片数据分区A
+	H264NALSliceB      H264NALType = 3  // 片数据分区B
+	H264NALSliceC      H264NALType = 4  // 片数据分区C
+	H264NALIDR         H264NALType = 5  // IDR图像的片
+	H264NALSEI         H264NALType = 6  // 补充增强信息
+	H264NALSPS         H264NALType = 7  // 序列参数集
+	H264NALPPS         H264NALType = 8  // 图像参数集
+	H264NALAUD         H264NALType = 9  // 访问单元分隔符
+	H264NALEndOfSeq    H264NALType = 10 // 序列结束
+	H264NALEndOfStream H264NALType = 11 // 码流结束
+	H264NALFiller      H264NALType = 12 // 填充数据
+)
+
+// H264NALUnit H.264 NAL单元
+type H264NALUnit struct {
+	Type   H264NALType // NAL单元类型
+	RefIdc uint8       // 参考级别
+	Data   []byte      // NAL单元数据（含1字节头，不含起始码）
+}
+
+// H264SPS H.264序列参数集
+type H264SPS struct {
+	ProfileIdc      uint8  // 档次
+	ConstraintFlags uint8  // 约束标志（constraint_set0~5）
+	LevelIdc        uint8  // 级别
+	ID              uint32 // 序列参数集ID
+	ChromaFormatIdc uint32 // 色度格式（0:单色 1:4:2:0 2:4:2:2 3:4:4:4）
+	BitDepthLuma    uint32 // 亮度位深
+	BitDepthChroma  uint32 // 色度位深
+	Width           uint32 // 图像宽度（已裁剪）
+	Height          uint32 // 图像高度（已裁剪）
+	FrameMbsOnly    bool   // 是否仅包含帧编码（非隔行）
+	NumUnitsInTick  uint32 // VUI时钟周期数（0表示未携带帧率信息）
+	TimeScale       uint32 // VUI时钟频率
+	FixedFrameRate  bool   // 是否为固定帧率
+}
+
+// H264PPS H.264图像参数集
+type H264PPS struct {
+	ID                 uint32 // 图像参数集ID
+	SPSID              uint32 // 引用的序列参数集ID
+	EntropyCodingCABAC bool   // 是否使用CABAC熵编码
+}
+
+// H264FrameInfo H.264帧解析结果
+type H264FrameInfo struct {
+	NALUnits []H264NALUnit // NAL单元列表
+	Keyframe bool          // 是否为关键帧（包含IDR片）
+	SPS      *H264SPS      // 帧内携带的序列参数集（未携带时为nil）
+	PPS      *H264PPS      // 帧内携带的图像参数集（未携带时为nil）
+}
+
+// 需要解析色度格式等扩展字段的档次
+var h264HighProfiles = map[uint8]bool{
+	100: true, 110: true, 122: true, 244: true, 44: true, 83: true, 86: true,
+	118: true, 128: true, 138: true, 139: true, 134: true, 135: true,
+}
+
+// ParseH264NALUnits 将Annex-B字节流拆分为H.264 NAL单元
+//
+//	@param	data	Annex-B字节流
+//	@return	NAL单元列表
+func ParseH264NALUnits(data []byte) []H264NALUnit {
+	nals := SplitAnnexB(data)
+	res := make([]H264NALUnit, 0, len(nals))
+	for _, nal := range nals {
+		res = append(res, H264NALUnit{
+			Type:   H264NALType(nal[0] & 0x1f),
+			RefIdc: nal[0] >> 5 & 0x03,
+			Data:   nal,
+		})
+	}
+	return res
+}
+
+// ParseH264Frame 解析H.264帧（Annex-B格式），识别关键帧并解析携带的参数集
+//
+//	@param	data	帧数据
+//	@return	帧解析结果
+//	@return	异常信息
+func ParseH264Frame(data []byte) (*H264FrameInfo, error) {
+	info := &H264FrameInfo{NALUnits: ParseH264NALUnits(data)}
+	if len(info.NALUnits) == 0 {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("no nal unit found"))
+	}
+
+	for _, nal := range info.NALUnits {
+		switch nal.Type {
+		case H264NALIDR:
+			info.Keyframe = true
+		case H264NALSPS:
+			sps, err := ParseH264SPS(nal.Data)
+			if err != nil {
+				return nil, err
+			}
+			info.SPS = sps
+		case H264NALPPS:
+			pps, err := ParseH264PPS(nal.Data)
+			if err != nil {
+				return nil, err
+			}
+			info.PPS = pps
+		}
+	}
+	return info, nil
+}
+
+// 跳过缩放矩阵
+func skipH264ScalingList(r *bitstreamReader, size int) {
+	last, next := int32(8), int32(8)
+	for i := 0; i < size; i++ {
+		if next != 0 {
+			next = (last + r.se() + 256) % 256
+		}
+		if next != 0 {
+			last = next
+		}
+	}
+}
+
+// ParseH264SPS 解析H.264序列参数集
+//
+//	@param	nal	SPS NAL单元（含1字节头，不含起始码）
+//	@return	序列参数集
+//	@return	异常信息
+func ParseH264SPS(nal []byte) (*H264SPS, error) {
+	if len(nal) < 4 || H264NALType(nal[0]&0x1f) != H264NALSPS {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("not a h264 sps nal unit"))
+	}
+	r := &bitstreamReader{data: unescapeRBSP(nal[1:])}
+	sps := &H264SPS{ChromaFormatIdc: 1, BitDepthLuma: 8, BitDepthChroma: 8}
+	sps.ProfileIdc = uint8(r.u(8))
+	sps.ConstraintFlags = uint8(r.u(8))
+	sps.LevelIdc = uint8(r.u(8))
+	sps.ID = r.ue()
+
+	// 高档次扩展字段
+	separateColourPlane := false
+	if h264HighProfiles[sps.ProfileIdc] {
+		sps.ChromaFormatIdc = r.ue()
+		if sps.ChromaFormatIdc == 3 {
+			separateColourPlane = r.flag()
+		}
+		sps.BitDepthLuma = r.ue() + 8
+		sps.BitDepthChroma = r.ue() + 8
+		r.flag() // qpprime_y_zero_transform_bypass_flag
+		if r.flag() {
+			lists := 8
+			if sps.ChromaFormatIdc == 3 {
+				lists = 12
+			}
+			for i := 0; i < lists; i++ {
+				if r.flag() {
+					size := 64
+					if i < 6 {
+						size = 16
+					}
+					skipH264ScalingList(r, size)
+				}
+			}
+		}
+	}
+
+	// 图像顺序
+	r.ue() // log2_max_frame_num_minus4
+	switch r.ue() {
+	case 0:
+		r.ue() // log2_max_pic_order_cnt_lsb_minus4
+	case 1:
+		r.flag() // delta_pic_order_always_zero_flag
+		r.se()   // offset_for_non_ref_pic
+		r.se()   // offset_for_top_to_bottom_field
+		n := r.ue()
+		for i := uint32(0); i < n && r.err == nil; i++ {
+			r.se()
+		}
+	}
+	r.ue()   // max_num_ref_frames
+	r.flag() // gaps_in_frame_num_value_allowed_flag
+
+	// 分辨率
+	widthMbs := r.ue() + 1
+	heightMapUnits := r.ue() + 1
+	sps.FrameMbsOnly = r.flag()
+	if !sps.FrameMbsOnly {
+		r.flag() // mb_adaptive_frame_field_flag
+	}
+	r.flag() // direct_8x8_inference_flag
+	var cropLeft, cropRight, cropTop, cropBottom uint32
+	if r.flag() {
+		cropLeft, cropRight, cropTop, cropBottom = r.ue(), r.ue(), r.ue(), r.ue()
+	}
+	fieldFactor := uint32(2)
+	if sps.FrameMbsOnly {
+		fieldFactor = 1
+	}
+	cropUnitX, cropUnitY := uint32(1), fieldFactor
+	if !separateColourPlane && sps.ChromaFormatIdc != 0 {
+		if sps.ChromaFormatIdc == 1 || sps.ChromaFormatIdc == 2 {
+			cropUnitX = 2
+		}
+		if sps.ChromaFormatIdc == 1 {
+			cropUnitY *= 2
+		}
+	}
+	sps.Width = widthMbs*16 - cropUnitX*(cropLeft+cropRight)
+	sps.Height = fieldFactor*heightMapUnits*16 - cropUnitY*(cropTop+cropBottom)
+
+	// VUI中的帧率信息
+	if r.flag() {
+		if r.flag() { // aspect_ratio_info_present_flag
+			if r.u(8) == 255 {
+				r.skip(32) // sar_width, sar_height
+			}
+		}
+		if r.flag() { // overscan_info_present_flag
+			r.flag()
+		}
+		if r.flag() { // video_signal_type_present_flag
+			r.skip(4)
+			if r.flag() {
+				r.skip(24)
+			}
+		}
+		if r.flag() { // chroma_loc_info_present_flag
+			r.ue()
+			r.ue()
+		}
+		if r.flag() { // timing_info_present_flag
+			sps.NumUnitsInTick = r.u(32)
+			sps.TimeScale = r.u(32)
+			sps.FixedFrameRate = r.flag()
+		}
+	}
+
+	if r.err != nil {
+		return nil, errors.Join(ErrParseBitstreamFailed, fmt.Errorf("h264 sps: %w", r.err))
+	}
+	if sps.Width == 0 || sps.Height == 0 || widthMbs*16 < sps.Width || fieldFactor*heightMapUnits*16 < sps.Height {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("h264 sps: invalid picture size"))
+	}
+	return sps, nil
+}
+
+// Profile 档次名称
+func (p *H264SPS) Profile() string {
+	switch p.ProfileIdc {
+	case 66:
+		if p.ConstraintFlags&0x40 != 0 {
+			return "Constrained Baseline"
+		}
+		return "Baseline"
+	case 77:
+		return "Main"
+	case 88:
+		return "Extended"
+	case 100:
+		return "High"
+	case 110:
+		return "High 10"
+	case 122:
+		return "High 4:2:2"
+	case 244:
+		return "High 4:4:4 Predictive"
+	case 44:
+		return "CAVLC 4:4:4 Intra"
+	default:
+		return fmt.Sprintf("Profile %d", p.ProfileIdc)
+	}
+}
+
+// Level 级别名称（如“4.1”）
+func (p *H264SPS) Level() string {
+	// 级别1b有两种表示方式
+	if p.LevelIdc == 9 || (p.LevelIdc == 11 && p.ConstraintFlags&0x10 != 0 && (p.ProfileIdc == 66 || p.ProfileIdc == 77 || p.ProfileIdc == 88)) {
+		return "1b"
+	}
+	return fmt.Sprintf("%d.%d", p.LevelIdc/10, p.LevelIdc%10)
+}
+
+// FrameRate 编码帧率（码流未携带帧率信息时返回0）
+func (p *H264SPS) FrameRate() float64 {
+	if p.NumUnitsInTick == 0 || p.TimeScale == 0 {
+		return 0
+	}
+	return float64(p.TimeScale) / float64(2*uint64(p.NumUnitsInTick))
+}
+
+// FrameInterval 编码帧间隔（码流未携带帧率信息时返回零值）
+func (p *H264SPS) FrameInterval() FrameInterval {
+	// 帧率为time_scale/(2*num_units_in_tick)
+	if p.NumUnitsInTick > math.MaxUint32/2 {
+		return NewFrameInterval(p.NumUnitsInTick, p.TimeScale/2)
+	}
+	return NewFrameInterval(2*p.NumUnitsInTick, p.TimeScale)
+}
+
+// Config 码流实际编码的配置信息
+func (p *H264SPS) Config() DeviceConfig {
+	return NewDeviceConfigWithInterval(p.Width, p.Height, p.FrameInterval(), FOURCC_H264)
+}
+
+// ParseH264PPS 解析H.264图像参数集（仅解析头部字段）
+//
+//	@param	nal	PPS NAL单元（含1字节头，不含起始码）
+//	@return	图像参数集
+//	@return	异常信息
+func ParseH264PPS(nal []byte) (*H264PPS, error) {
+	if len(nal) < 2 || H264NALType(nal[0]&0x1f) != H264NALPPS {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("not a h264 pps nal unit"))
+	}
+	r := &bitstreamReader{data: unescapeRBSP(nal[1:])}
+	pps := &H264PPS{ID: r.ue(), SPSID: r.ue(), EntropyCodingCABAC: r.flag()}
+	if r.err != nil {
+		return nil, errors.Join(ErrParseBitstreamFailed, fmt.Errorf("h264 pps: %w", r.err))
+	}
+	return pps, nil
+}
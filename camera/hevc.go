@@ -0,0 +1,291 @@
+package camera
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// HEVCNALType H.265 NAL单元类型
+type HEVCNALType uint8
+
+const (
+	HEVCNALTrailN   HEVCNALType = 0  // 非参考的普通图像片
+	HEVCNALTrailR   HEVCNALType = 1  // 参考的普通图像片
+	HEVCNALBLAWLP   HEVCNALType = 16 // 断链访问图像（IRAP起始）
+	HEVCNALIDRWRADL HEVCNALType = 19 // 带RADL的IDR图像
+	HEVCNALIDRNLP   HEVCNALType = 20 // 不带前置图像的IDR图像
+	HEVCNALCRA      HEVCNALType = 21 // 清理随机访问图像
+	HEVCNALIRAPMax  HEVCNALType = 23 // IRAP类型上限
+	HEVCNALVPS      HEVCNALType = 32 // 视频参数集
+	HEVCNALSPS      HEVCNALType = 33 // 序列参数集
+	HEVCNALPPS      HEVCNALType = 34 // 图像参数集
+	HEVCNALAUD      HEVCNALType = 35 // 访问单元分隔符
+	HEVCNALSEIPrev  HEVCNALType = 39 // 前缀补充增强信息
+	HEVCNALSEISuff  HEVCNALType = 40 // 后缀补充增强信息
+)
+
+// IsIRAP 是否为随机访问点图像（BLA、IDR、CRA，可作为关键帧）
+func (p HEVCNALType) IsIRAP() bool {
+	return p >= HEVCNALBLAWLP && p <= HEVCNALIRAPMax
+}
+
+// HEVCNALUnit H.265 NAL单元
+type HEVCNALUnit struct {
+	Type HEVCNALType // NAL单元类型
+	Data []byte      // NAL单元数据（含2字节头，不含起始码）
+}
+
+// ParseHEVCNALUnits 将Annex-B字节流拆分为H.265 NAL单元（忽略不足2字节头的单元）
+//
+//	@param	data	Annex-B字节流
+//	@return	NAL单元列表
+func ParseHEVCNALUnits(data []byte) []HEVCNALUnit {
+	nals := SplitAnnexB(data)
+	res := make([]HEVCNALUnit, 0, len(nals))
+	for _, nal := range nals {
+		if len(nal) < 2 {
+			continue
+		}
+		res = append(res, HEVCNALUnit{Type: HEVCNALType(nal[0] >> 1 & 0x3f), Data: nal})
+	}
+	return res
+}
+
+// HEVCSPS H.265序列参数集（仅包含封装所需的字段）
+type HEVCSPS struct {
+	MaxSubLayers      uint8  // 最大时域子层数
+	TemporalIDNesting bool   // 时域ID嵌套标志
+	ProfileSpace      uint8  // 档次空间
+	TierFlag          bool   // 是否为High层级
+	ProfileIdc        uint8  // 档次
+	ProfileCompat     uint32 // 档次兼容标志
+	ConstraintFlags   uint64 // 约束标志（48位）
+	LevelIdc          uint8  // 级别
+	ChromaFormatIdc   uint32 // 色度格式
+	BitDepthLuma      uint32 // 亮度位深
+	BitDepthChroma    uint32 // 色度位深
+	Width             uint32 // 图像宽度（已裁剪）
+	Height            uint32 // 图像高度（已裁剪）
+}
+
+// ParseHEVCSPS 解析H.265序列参数集
+//
+//	@param	nal	SPS NAL单元（含2字节头，不含起始码）
+//	@return	序列参数集
+//	@return	异常信息
+func ParseHEVCSPS(nal []byte) (*HEVCSPS, error) {
+	if len(nal) < 3 || HEVCNALType(nal[0]>>1&0x3f) != HEVCNALSPS {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("not a hevc sps nal unit"))
+	}
+	r := &bitstreamReader{data: unescapeRBSP(nal[2:])}
+	sps := &HEVCSPS{}
+	r.u(4) // sps_video_parameter_set_id
+	sps.MaxSubLayers = uint8(r.u(3)) + 1
+	sps.TemporalIDNesting = r.flag()
+
+	// profile_tier_level
+	sps.ProfileSpace = uint8(r.u(2))
+	sps.TierFlag = r.flag()
+	sps.ProfileIdc = uint8(r.u(5))
+	sps.ProfileCompat = r.u(32)
+	sps.ConstraintFlags = uint64(r.u(16))<<32 | uint64(r.u(32))
+	sps.LevelIdc = uint8(r.u(8))
+	subLayers := int(sps.MaxSubLayers) - 1
+	profilePresent, levelPresent := make([]bool, subLayers), make([]bool, subLayers)
+	for i := 0; i < subLayers; i++ {
+		profilePresent[i], levelPresent[i] = r.flag(), r.flag()
+	}
+	if subLayers > 0 {
+		r.skip(2 * (8 - subLayers))
+	}
+	for i := 0; i < subLayers; i++ {
+		if profilePresent[i] {
+			r.skip(88)
+		}
+		if levelPresent[i] {
+			r.skip(8)
+		}
+	}
+
+	// 色度格式与分辨率
+	r.ue() // sps_seq_parameter_set_id
+	sps.ChromaFormatIdc = r.ue()
+	if sps.ChromaFormatIdc == 3 {
+		r.flag() // separate_colour_plane_flag
+	}
+	sps.Width, sps.Height = r.ue(), r.ue()
+	if r.flag() { // conformance_window_flag
+		unitX, unitY := uint32(1), uint32(1)
+		if sps.ChromaFormatIdc == 1 || sps.ChromaFormatIdc == 2 {
+			unitX = 2
+		}
+		if sps.ChromaFormatIdc == 1 {
+			unitY = 2
+		}
+		left, right, top, bottom := r.ue(), r.ue(), r.ue(), r.ue()
+		if unitX*(left+right) >= sps.Width || unitY*(top+bottom) >= sps.Height {
+			return nil, errors.Join(ErrParseBitstreamFailed, errors.New("hevc sps: invalid conformance window"))
+		}
+		sps.Width -= unitX * (left + right)
+		sps.Height -= unitY * (top + bottom)
+	}
+	sps.BitDepthLuma = r.ue() + 8
+	sps.BitDepthChroma = r.ue() + 8
+
+	if r.err != nil {
+		return nil, errors.Join(ErrParseBitstreamFailed, fmt.Errorf("hevc sps: %w", r.err))
+	}
+	if sps.Width == 0 || sps.Height == 0 {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("hevc sps: invalid picture size"))
+	}
+	return sps, nil
+}
+
+// HEVCDecoderConfig H.265解码器配置记录（MP4中的hvcC，ISO/IEC 14496-15）
+type HEVCDecoderConfig struct {
+	SPS        HEVCSPS  // 首个序列参数集的解析结果
+	LengthSize int      // NAL单元长度前缀字节数
+	VPS        [][]byte // 视频参数集（含2字节头）
+	SPSList    [][]byte // 序列参数集（含2字节头）
+	PPS        [][]byte // 图像参数集（含2字节头）
+}
+
+// NewHEVCDecoderConfig 根据参数集创建hvcC
+//
+//	@param	vps	视频参数集列表
+//	@param	sps	序列参数集列表
+//	@param	pps	图像参数集列表
+//	@return	解码器配置记录
+//	@return	异常信息
+func NewHEVCDecoderConfig(vps, sps, pps [][]byte) (*HEVCDecoderConfig, error) {
+	if len(vps) == 0 || len(sps) == 0 || len(pps) == 0 {
+		return nil, errors.Join(ErrInvalidParam, fmt.Errorf("need vps, sps and pps, got %d, %d and %d", len(vps), len(sps), len(pps)))
+	}
+	info, err := ParseHEVCSPS(sps[0])
+	if err != nil {
+		return nil, err
+	}
+	return &HEVCDecoderConfig{SPS: *info, LengthSize: DefaultNALLengthSize, VPS: vps, SPSList: sps, PPS: pps}, nil
+}
+
+// NewHEVCDecoderConfigFromAnnexB 从携带参数集的Annex-B帧（通常为关键帧）创建hvcC
+//
+//	@param	data	Annex-B帧数据
+//	@return	解码器配置记录
+//	@return	异常信息
+func NewHEVCDecoderConfigFromAnnexB(data []byte) (*HEVCDecoderConfig, error) {
+	var vps, sps, pps [][]byte
+	for _, nal := range ParseHEVCNALUnits(data) {
+		switch nal.Type {
+		case HEVCNALVPS:
+			vps = append(vps, nal.Data)
+		case HEVCNALSPS:
+			sps = append(sps, nal.Data)
+		case HEVCNALPPS:
+			pps = append(pps, nal.Data)
+		}
+	}
+	return NewHEVCDecoderConfig(vps, sps, pps)
+}
+
+// Marshal 序列化为hvcC字节
+//
+//	@return	hvcC数据
+//	@return	异常信息
+func (p *HEVCDecoderConfig) Marshal() ([]byte, error) {
+	if err := checkNALLengthSize(p.LengthSize); err != nil {
+		return nil, err
+	}
+	s := &p.SPS
+	res := []byte{1, s.ProfileSpace<<6 | s.ProfileIdc&0x1f}
+	if s.TierFlag {
+		res[1] |= 0x20
+	}
+	res = binary.BigEndian.AppendUint32(res, s.ProfileCompat)
+	res = append(res, byte(s.ConstraintFlags>>40), byte(s.ConstraintFlags>>32), byte(s.ConstraintFlags>>24),
+		byte(s.ConstraintFlags>>16), byte(s.ConstraintFlags>>8), byte(s.ConstraintFlags))
+	res = append(res,
+		s.LevelIdc,
+		0xf0, 0x00, // min_spatial_segmentation_idc
+		0xfc,                                 // parallelismType
+		0xfc|byte(s.ChromaFormatIdc&0x03),    // chromaFormat
+		0xf8|byte((s.BitDepthLuma-8)&0x07),   // bitDepthLumaMinus8
+		0xf8|byte((s.BitDepthChroma-8)&0x07), // bitDepthChromaMinus8
+		0x00, 0x00,                           // avgFrameRate
+	)
+	last := byte((s.MaxSubLayers&0x07)<<3) | byte(p.LengthSize-1)
+	if s.TemporalIDNesting {
+		last |= 0x04
+	}
+	res = append(res, last, 3)
+
+	// 参数集数组
+	var err error
+	for _, arr := range []struct {
+		typ  HEVCNALType
+		nals [][]byte
+	}{{HEVCNALVPS, p.VPS}, {HEVCNALSPS, p.SPSList}, {HEVCNALPPS, p.PPS}} {
+		if len(arr.nals) > 0xffff {
+			return nil, errors.Join(ErrInvalidParam, errors.New("too many parameter sets"))
+		}
+		res = append(res, 0x80|byte(arr.typ))
+		res = binary.BigEndian.AppendUint16(res, uint16(len(arr.nals)))
+		for _, nal := range arr.nals {
+			if res, err = appendParameterSet(res, nal); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
+}
+
+// ParseHEVCDecoderConfig 解析hvcC（从首个SPS中恢复档次、分辨率等字段）
+//
+//	@param	data	hvcC数据
+//	@return	解码器配置记录
+//	@return	异常信息
+func ParseHEVCDecoderConfig(data []byte) (*HEVCDecoderConfig, error) {
+	if len(data) < 23 || data[0] != 1 {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("invalid hvcC record"))
+	}
+	var vps, sps, pps [][]byte
+	pos := 23
+	for i := 0; i < int(data[22]); i++ {
+		if pos+3 > len(data) {
+			return nil, errors.Join(ErrParseBitstreamFailed, errors.New("truncated hvcC array"))
+		}
+		typ, count := HEVCNALType(data[pos]&0x3f), int(binary.BigEndian.Uint16(data[pos+1:]))
+		pos += 3
+		for j := 0; j < count; j++ {
+			nal, next, err := readParameterSet(data, pos)
+			if err != nil {
+				return nil, errors.Join(ErrParseBitstreamFailed, err)
+			}
+			pos = next
+			switch typ {
+			case HEVCNALVPS:
+				vps = append(vps, nal)
+			case HEVCNALSPS:
+				sps = append(sps, nal)
+			case HEVCNALPPS:
+				pps = append(pps, nal)
+			}
+		}
+	}
+	p, err := NewHEVCDecoderConfig(vps, sps, pps)
+	if err != nil {
+		return nil, err
+	}
+	p.LengthSize = int(data[21]&0x03) + 1
+	if p.LengthSize == 3 {
+		return nil, errors.Join(ErrParseBitstreamFailed, errors.New("invalid hvcC nal length size"))
+	}
+	return p, nil
+}
+
+// AnnexBParameterSets 以Annex-B格式输出全部参数集（可放在关键帧之前供解码器初始化）
+func (p *HEVCDecoderConfig) AnnexBParameterSets() []byte {
+	nals := append(append([][]byte(nil), p.VPS...), p.SPSList...)
+	return joinAnnexB(append(nals, p.PPS...))
+}
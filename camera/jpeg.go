@@ -0,0 +1,234 @@
+package camera
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+)
+
+// JPEG标记
+const (
+	jpegMarkerSOF0 = 0xc0 // 基线DCT帧开始
+	jpegMarkerDHT  = 0xc4 // 定义Huffman表
+	jpegMarkerRST0 = 0xd0 // 重启标记0
+	jpegMarkerRST7 = 0xd7 // 重启标记7
+	jpegMarkerSOI  = 0xd8 // 图像开始
+	jpegMarkerEOI  = 0xd9 // 图像结束
+	jpegMarkerSOS  = 0xda // 扫描开始
+	jpegMarkerDQT  = 0xdb // 定义量化表
+	jpegMarkerDRI  = 0xdd // 定义重启间隔
+	jpegMarkerAPP0 = 0xe0 // 应用段0（JFIF）
+	jpegMarkerTEM  = 0x01 // 临时标记
+)
+
+// 标准Huffman表（ITU-T T.81 Annex K.3）
+//
+// 依次为亮度DC、亮度AC、色度DC、色度AC，每张表由“表类型/编号”、16个码长计数及符号值组成
+var jpegStdHuffmanTables = [][]byte{
+	append([]byte{0x00,
+		0, 1, 5, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0},
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
+	),
+	append([]byte{0x10,
+		0, 2, 1, 3, 3, 2, 4, 3, 5, 5, 4, 4, 0, 0, 1, 0x7d},
+		0x01, 0x02, 0x03, 0x00, 0x04, 0x11, 0x05, 0x12,
+		0x21, 0x31, 0x41, 0x06, 0x13, 0x51, 0x61, 0x07,
+		0x22, 0x71, 0x14, 0x32, 0x81, 0x91, 0xa1, 0x08,
+		0x23, 0x42, 0xb1, 0xc1, 0x15, 0x52, 0xd1, 0xf0,
+		0x24, 0x33, 0x62, 0x72, 0x82, 0x09, 0x0a, 0x16,
+		0x17, 0x18, 0x19, 0x1a, 0x25, 0x26, 0x27, 0x28,
+		0x29, 0x2a, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39,
+		0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49,
+		0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59,
+		0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69,
+		0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79,
+		0x7a, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89,
+		0x8a, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98,
+		0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7,
+		0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6,
+		0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3, 0xc4, 0xc5,
+		0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2, 0xd3, 0xd4,
+		0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xe1, 0xe2,
+		0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea,
+		0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+		0xf9, 0xfa,
+	),
+	append([]byte{0x01,
+		0, 3, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0},
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
+	),
+	append([]byte{0x11,
+		0, 2, 1, 2, 4, 4, 3, 4, 7, 5, 4, 4, 0, 1, 2, 0x77},
+		0x00, 0x01, 0x02, 0x03, 0x11, 0x04, 0x05, 0x21,
+		0x31, 0x06, 0x12, 0x41, 0x51, 0x07, 0x61, 0x71,
+		0x13, 0x22, 0x32, 0x81, 0x08, 0x14, 0x42, 0x91,
+		0xa1, 0xb1, 0xc1, 0x09, 0x23, 0x33, 0x52, 0xf0,
+		0x15, 0x62, 0x72, 0xd1, 0x0a, 0x16, 0x24, 0x34,
+		0xe1, 0x25, 0xf1, 0x17, 0x18, 0x19, 0x1a, 0x26,
+		0x27, 0x28, 0x29, 0x2a, 0x35, 0x36, 0x37, 0x38,
+		0x39, 0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48,
+		0x49, 0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58,
+		0x59, 0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68,
+		0x69, 0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78,
+		0x79, 0x7a, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87,
+		0x88, 0x89, 0x8a, 0x92, 0x93, 0x94, 0x95, 0x96,
+		0x97, 0x98, 0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5,
+		0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4,
+		0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3,
+		0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2,
+		0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda,
+		0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9,
+		0xea, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+		0xf9, 0xfa,
+	),
+}
+
+// 标准Huffman表的完整DHT段（含标记和长度）
+var jpegStdDHTSegment = func() []byte {
+	size := 2
+	for _, t := range jpegStdHuffmanTables {
+		size += len(t)
+	}
+	seg := []byte{0xff, jpegMarkerDHT, byte(size >> 8), byte(size)}
+	for _, t := range jpegStdHuffmanTables {
+		seg = append(seg, t...)
+	}
+	return seg
+}()
+
+// JPEG头部段信息
+type jpegSegment struct {
+	marker byte // 段标记
+	offset int  // 标记（0xFF）在数据中的偏移
+	size   int  // 段总长度（含标记）
+}
+
+// 遍历JPEG头部段直到扫描开始（SOS）
+//
+//	@param	data	JPEG数据
+//	@param	fn		段回调，返回false时停止遍历
+//	@return	异常信息
+func walkJpegSegments(data []byte, fn func(seg jpegSegment) bool) error {
+	if len(data) < 2 || data[0] != 0xff || data[1] != jpegMarkerSOI {
+		return errors.New("missing SOI marker")
+	}
+
+	for pos := 2; ; {
+		// 跳过标记前的填充字节
+		for pos < len(data) && data[pos] == 0xff && pos+1 < len(data) && data[pos+1] == 0xff {
+			pos++
+		}
+		if pos+1 >= len(data) {
+			return errors.New("unexpected end of header")
+		}
+		if data[pos] != 0xff {
+			return fmt.Errorf("invalid marker at offset %d", pos)
+		}
+		marker := data[pos+1]
+
+		// 无长度字段的独立标记
+		if marker == jpegMarkerTEM || (marker >= jpegMarkerRST0 && marker <= jpegMarkerRST7) {
+			if !fn(jpegSegment{marker: marker, offset: pos, size: 2}) {
+				return nil
+			}
+			pos += 2
+			continue
+		}
+		if marker == jpegMarkerEOI {
+			return errors.New("EOI before SOS")
+		}
+
+		// 带长度字段的段
+		if pos+4 > len(data) {
+			return errors.New("unexpected end of header")
+		}
+		size := int(data[pos+2])<<8 | int(data[pos+3])
+		if size < 2 || pos+2+size > len(data) {
+			return fmt.Errorf("invalid segment length %d at offset %d", size, pos)
+		}
+		if !fn(jpegSegment{marker: marker, offset: pos, size: size + 2}) || marker == jpegMarkerSOS {
+			return nil
+		}
+		pos += size + 2
+	}
+}
+
+// InjectHuffmanTables 为缺少Huffman表的JPEG数据注入标准Huffman表
+//
+// 多数UVC摄像头输出的MJPEG帧省略了DHT段，默认使用ITU-T T.81 Annex K中的标准表，
+// 此处在扫描开始（SOS）前插入标准DHT段，已包含DHT段时原样返回
+//
+//	@param	data	JPEG数据
+//	@return	可被标准解码器解码的JPEG数据
+//	@return	异常信息
+func InjectHuffmanTables(data []byte) ([]byte, error) {
+	// 查找DHT段与SOS段
+	sos, hasDHT := -1, false
+	err := walkJpegSegments(data, func(seg jpegSegment) bool {
+		switch seg.marker {
+		case jpegMarkerDHT:
+			hasDHT = true
+			return false
+		case jpegMarkerSOS:
+			sos = seg.offset
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Join(ErrDecodeJpegImageFailed, err)
+	}
+	if hasDHT {
+		return data, nil
+	}
+	if sos < 0 {
+		return nil, errors.Join(ErrDecodeJpegImageFailed, errors.New("missing SOS marker"))
+	}
+
+	// 在SOS前插入标准DHT段
+	res := make([]byte, 0, len(data)+len(jpegStdDHTSegment))
+	res = append(res, data[:sos]...)
+	res = append(res, jpegStdDHTSegment...)
+	res = append(res, data[sos:]...)
+	return res, nil
+}
+
+// DecodeMJPEG 解码MJPEG帧（缺少Huffman表时自动注入标准表）
+//
+//	@param	data	MJPEG帧数据
+//	@return	YCbCr图像（灰度JPEG的色度平面填充为中性值）
+//	@return	异常信息
+func DecodeMJPEG(data []byte) (*image.YCbCr, error) {
+	// 补全Huffman表
+	src, err := InjectHuffmanTables(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// 解码
+	img, err := jpeg.Decode(bytes.NewReader(src))
+	if err != nil {
+		return nil, errors.Join(ErrDecodeJpegImageFailed, err)
+	}
+	switch img := img.(type) {
+	case *image.YCbCr:
+		return img, nil
+	case *image.Gray:
+		return grayToYCbCr(img), nil
+	default:
+		return nil, errors.Join(ErrDecodeJpegImageFailed, fmt.Errorf("unsupported color model %T", img))
+	}
+}
+
+// 将灰度图像转换为4:2:0的YCbCr图像
+func grayToYCbCr(img *image.Gray) *image.YCbCr {
+	res := image.NewYCbCr(img.Rect, image.YCbCrSubsampleRatio420)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		copy(res.Y[res.YOffset(img.Rect.Min.X, y):], img.Pix[img.PixOffset(img.Rect.Min.X, y):img.PixOffset(img.Rect.Max.X, y)])
+	}
+	for i := range res.Cb {
+		res.Cb[i], res.Cr[i] = 0x80, 0x80
+	}
+	return res
+}
@@ -0,0 +1,88 @@
+package camera
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// CheckMJPEG 校验MJPEG帧的完整性
+//
+// 检查SOI/EOI标记、头部段长度、扫描数据中的非法标记与重启标记顺序、
+// EOI之后的多余数据（允许UVC传输常见的0x00填充），USB带宽不足时常见的半帧会被识别为截断
+//
+//	@param	data	MJPEG帧数据
+//	@return	异常信息（帧损坏时为ErrFrameCorrupted）
+func CheckMJPEG(data []byte) error {
+	if len(data) == 0 {
+		return errors.Join(ErrFrameCorrupted, errors.New("empty frame"))
+	}
+
+	// 校验头部并定位扫描数据
+	scan := -1
+	err := walkJpegSegments(data, func(seg jpegSegment) bool {
+		if seg.marker == jpegMarkerSOS {
+			scan = seg.offset + seg.size
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Join(ErrFrameCorrupted, err)
+	}
+	if scan < 0 {
+		return errors.Join(ErrFrameCorrupted, errors.New("missing SOS marker"))
+	}
+
+	// 逐个检查扫描数据中的标记
+	nextRST := 0
+	for pos := scan; ; {
+		i := bytes.IndexByte(data[pos:], 0xff)
+		if i < 0 || pos+i+1 >= len(data) {
+			return errors.Join(ErrFrameCorrupted, fmt.Errorf("truncated scan, missing EOI marker (%d bytes)", len(data)))
+		}
+		pos += i
+		marker := data[pos+1]
+		switch {
+		case marker == 0x00:
+			// 填充的0xFF数据
+			pos += 2
+		case marker == 0xff:
+			// 标记前的填充字节
+			pos++
+		case marker >= jpegMarkerRST0 && marker <= jpegMarkerRST7:
+			// 重启标记必须按0~7循环出现，跳号说明丢失了数据
+			if int(marker-jpegMarkerRST0) != nextRST {
+				return errors.Join(ErrFrameCorrupted, fmt.Errorf("restart marker RST%d out of order at offset %d, want RST%d", marker-jpegMarkerRST0, pos, nextRST))
+			}
+			nextRST = (nextRST + 1) % 8
+			pos += 2
+		case marker == jpegMarkerEOI:
+			// EOI之后仅允许0x00填充
+			for i, b := range data[pos+2:] {
+				if b != 0x00 {
+					return errors.Join(ErrFrameCorrupted, fmt.Errorf("trailing garbage at offset %d", pos+2+i))
+				}
+			}
+			return nil
+		default:
+			return errors.Join(ErrFrameCorrupted, fmt.Errorf("unexpected marker 0x%02x in scan at offset %d", marker, pos))
+		}
+	}
+}
+
+// CheckFrame 校验帧数据的完整性（目前仅校验MJPEG/JPEG帧，其他格式直接通过）
+//
+//	@param	data	帧数据
+//	@param	info	帧信息
+//	@return	异常信息（帧损坏时为ErrFrameCorrupted）
+func CheckFrame(data []byte, info *DeviceConfig) error {
+	if info == nil {
+		return nil
+	}
+	switch info.Format {
+	case FOURCC_MJPEG, FOURCC_JPEG:
+		return CheckMJPEG(data)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,478 @@
+package camera
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultJpegQuality 默认JPEG编码质量
+const DefaultJpegQuality = 75
+
+// JpegEncodeOptions JPEG编码参数
+type JpegEncodeOptions struct {
+	Quality         int // 编码质量（1~100，0表示使用默认质量）
+	RestartInterval int // 重启间隔（每隔多少个MCU插入一个重启标记，0表示不插入）
+}
+
+// Z字形扫描顺序对应的自然顺序下标
+var jpegZigzag = [64]int{
+	0, 1, 8, 16, 9, 2, 3, 10,
+	17, 24, 32, 25, 18, 11, 4, 5,
+	12, 19, 26, 33, 40, 48, 41, 34,
+	27, 20, 13, 6, 7, 14, 21, 28,
+	35, 42, 49, 56, 57, 50, 43, 36,
+	29, 22, 15, 23, 30, 37, 44, 51,
+	58, 59, 52, 45, 38, 31, 39, 46,
+	53, 60, 61, 54, 47, 55, 62, 63,
+}
+
+// 标准量化表（ITU-T T.81 Annex K.1，自然顺序，依次为亮度、色度）
+var jpegStdQuantTables = [2][64]int{
+	{
+		16, 11, 10, 16, 24, 40, 51, 61,
+		12, 12, 14, 19, 26, 58, 60, 55,
+		14, 13, 16, 24, 40, 57, 69, 56,
+		14, 17, 22, 29, 51, 87, 80, 62,
+		18, 22, 37, 56, 68, 109, 103, 77,
+		24, 35, 55, 64, 81, 104, 113, 92,
+		49, 64, 78, 87, 103, 121, 120, 101,
+		72, 92, 95, 98, 112, 100, 103, 99,
+	},
+	{
+		17, 18, 24, 47, 99, 99, 99, 99,
+		18, 21, 26, 66, 99, 99, 99, 99,
+		24, 26, 56, 99, 99, 99, 99, 99,
+		47, 66, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+	},
+}
+
+// AAN浮点DCT的缩放系数
+var jpegAANScale = [8]float32{
+	1.0, 1.387039845, 1.306562965, 1.175875602,
+	1.0, 0.785694958, 0.541196100, 0.275899379,
+}
+
+// Huffman编码表（按符号值索引）
+type jpegHuffmanCode struct {
+	code [256]uint32 // 码字
+	size [256]uint8  // 码长
+}
+
+// 标准Huffman编码表（依次为亮度DC、亮度AC、色度DC、色度AC）
+var jpegStdHuffmanCodes = func() (res [4]jpegHuffmanCode) {
+	for i, t := range jpegStdHuffmanTables {
+		// 按码长依次分配规范Huffman码字
+		bits, vals := t[1:17], t[17:]
+		code, k := uint32(0), 0
+		for l := 0; l < 16; l++ {
+			for n := 0; n < int(bits[l]); n++ {
+				res[i].code[vals[k]] = code
+				res[i].size[vals[k]] = uint8(l + 1)
+				code++
+				k++
+			}
+			code <<= 1
+		}
+	}
+	return res
+}()
+
+// 有限范围YUV转全范围YCbCr的查找表（依次为亮度、色度）
+var jpegRangeLUT = func() (res [2][256]uint8) {
+	for i := 0; i < 256; i++ {
+		res[0][i] = clampUint8(int32((i-16)*255*2+219) / (219 * 2))
+		c := (i - 128) * 255
+		if c < 0 {
+			c -= 112
+		} else {
+			c += 112
+		}
+		res[1][i] = clampUint8(int32(128 + c/224))
+	}
+	return res
+}()
+
+// 待编码的YCbCr平面（已转换为全范围）
+type jpegPlanes struct {
+	y, cb, cr []byte // 各平面数据（无行尾填充）
+	w, h      int    // 亮度平面尺寸
+	cw, ch    int    // 色度平面尺寸
+	vSub      int    // 色度垂直采样因子（4:2:2为1，4:2:0为2）
+}
+
+// 从帧中提取YCbCr平面
+//
+//	@param	frame	YUV帧
+//	@return	YCbCr平面
+//	@return	异常信息
+func extractJpegPlanes(frame *Frame) (*jpegPlanes, error) {
+	info := &frame.Config
+	w, h := int(info.Width), int(info.Height)
+	if w > 0xffff || h > 0xffff {
+		return nil, errors.Join(ErrFrameSizeMismatch, fmt.Errorf("size %dx%d exceeds jpeg limit", w, h))
+	}
+	lumaLUT, chromaLUT := &jpegRangeLUT[0], &jpegRangeLUT[1]
+
+	// 打包YUV 4:2:2
+	if layout, ok := yuv422Layouts[info.Format]; ok {
+		cw := (w + 1) / 2
+		stride, err := frameStride(frame.Data, info, cw*4)
+		if err != nil {
+			return nil, err
+		}
+		p := &jpegPlanes{
+			y: make([]byte, w*h), cb: make([]byte, cw*h), cr: make([]byte, cw*h),
+			w: w, h: h, cw: cw, ch: h, vSub: 1,
+		}
+		for y := 0; y < h; y++ {
+			row := frame.Data[y*stride:]
+			for x := 0; x < w; x++ {
+				p.y[y*w+x] = lumaLUT[row[x/2*4+layout[x%2*2]]]
+			}
+			for cx := 0; cx < cw; cx++ {
+				p.cb[y*cw+cx] = chromaLUT[row[cx*4+layout[1]]]
+				p.cr[y*cw+cx] = chromaLUT[row[cx*4+layout[3]]]
+			}
+		}
+		return p, nil
+	}
+
+	// 平面及半平面YUV 4:2:0
+	switch info.Format {
+	case FOURCC_NV12, FOURCC_NV21, FOURCC_YUV420, FOURCC_YVU420:
+	default:
+		return nil, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q cannot be encoded to jpeg", info.Format))
+	}
+	if err := checkYUV420(frame.Data, info, info.Format); err != nil {
+		return nil, err
+	}
+	ySize, cSize := yuv420PlaneSize(info)
+	p := &jpegPlanes{
+		y: make([]byte, ySize), cb: make([]byte, cSize), cr: make([]byte, cSize),
+		w: w, h: h, cw: (w + 1) / 2, ch: (h + 1) / 2, vSub: 2,
+	}
+	for i, v := range frame.Data[:ySize] {
+		p.y[i] = lumaLUT[v]
+	}
+	chroma := frame.Data[ySize : ySize+2*cSize]
+	switch info.Format {
+	case FOURCC_NV12:
+		for i := 0; i < cSize; i++ {
+			p.cb[i], p.cr[i] = chromaLUT[chroma[2*i]], chromaLUT[chroma[2*i+1]]
+		}
+	case FOURCC_NV21:
+		for i := 0; i < cSize; i++ {
+			p.cr[i], p.cb[i] = chromaLUT[chroma[2*i]], chromaLUT[chroma[2*i+1]]
+		}
+	case FOURCC_YUV420:
+		for i := 0; i < cSize; i++ {
+			p.cb[i], p.cr[i] = chromaLUT[chroma[i]], chromaLUT[chroma[cSize+i]]
+		}
+	case FOURCC_YVU420:
+		for i := 0; i < cSize; i++ {
+			p.cr[i], p.cb[i] = chromaLUT[chroma[i]], chromaLUT[chroma[cSize+i]]
+		}
+	}
+	return p, nil
+}
+
+// JPEG熵编码写入器
+type jpegWriter struct {
+	w    *bufio.Writer // 输出
+	bits uint32        // 待写出的位
+	n    uint          // 待写出的位数
+	err  error         // 首个写入错误
+}
+
+// 写入字节（熵编码数据中的0xFF需要填充0x00）
+func (p *jpegWriter) writeByte(b byte, stuff bool) {
+	if p.err != nil {
+		return
+	}
+	if p.err = p.w.WriteByte(b); p.err == nil && stuff && b == 0xff {
+		p.err = p.w.WriteByte(0x00)
+	}
+}
+
+// 写入标记段
+func (p *jpegWriter) writeSegment(marker byte, payload []byte) {
+	if p.err != nil {
+		return
+	}
+	size := len(payload) + 2
+	_, p.err = p.w.Write(append([]byte{0xff, marker, byte(size >> 8), byte(size)}, payload...))
+}
+
+// 写入若干位（高位在前）
+func (p *jpegWriter) writeBits(bits uint32, n uint8) {
+	p.bits = p.bits<<n | bits&(1<<n-1)
+	p.n += uint(n)
+	for p.n >= 8 {
+		p.n -= 8
+		p.writeByte(byte(p.bits>>p.n), true)
+	}
+}
+
+// 以1填充并写出剩余位
+func (p *jpegWriter) flushBits() {
+	if p.n > 0 {
+		p.writeBits(0x7f, uint8(8-p.n))
+	}
+	p.bits = 0
+}
+
+// 写入Huffman编码的符号
+func (p *jpegWriter) writeSymbol(h *jpegHuffmanCode, sym byte) {
+	p.writeBits(h.code[sym], h.size[sym])
+}
+
+// 计算系数的位长度类别并返回其附加位
+func jpegCategory(v int) (uint8, uint32) {
+	a := v
+	if a < 0 {
+		a = -a
+		v--
+	}
+	n := uint8(0)
+	for a > 0 {
+		a >>= 1
+		n++
+	}
+	return n, uint32(v)
+}
+
+// 8x8块编码器
+type jpegBlockEncoder struct {
+	divisor [2][64]float32 // 含AAN缩放的量化除数（依次为亮度、色度）
+	quant   [2][64]byte    // 量化表（Z字形顺序，用于DQT段）
+}
+
+// 创建8x8块编码器
+func newJpegBlockEncoder(quality int) *jpegBlockEncoder {
+	// IJG质量缩放
+	scale := 200 - 2*quality
+	if quality < 50 {
+		scale = 5000 / quality
+	}
+	enc := &jpegBlockEncoder{}
+	for t := 0; t < 2; t++ {
+		for i, n := range jpegZigzag {
+			q := (jpegStdQuantTables[t][n]*scale + 50) / 100
+			if q < 1 {
+				q = 1
+			} else if q > 255 {
+				q = 255
+			}
+			enc.quant[t][i] = byte(q)
+			enc.divisor[t][n] = 1 / (float32(q) * jpegAANScale[n/8] * jpegAANScale[n%8] * 8)
+		}
+	}
+	return enc
+}
+
+// 一维AAN浮点前向DCT（原地计算，步长为stride）
+func jpegFDCT1D(d []float32, stride int) {
+	d0, d1, d2, d3 := d[0], d[stride], d[2*stride], d[3*stride]
+	d4, d5, d6, d7 := d[4*stride], d[5*stride], d[6*stride], d[7*stride]
+
+	tmp0, tmp7 := d0+d7, d0-d7
+	tmp1, tmp6 := d1+d6, d1-d6
+	tmp2, tmp5 := d2+d5, d2-d5
+	tmp3, tmp4 := d3+d4, d3-d4
+
+	// 偶数部分
+	tmp10, tmp13 := tmp0+tmp3, tmp0-tmp3
+	tmp11, tmp12 := tmp1+tmp2, tmp1-tmp2
+	d[0] = tmp10 + tmp11
+	d[4*stride] = tmp10 - tmp11
+	z1 := (tmp12 + tmp13) * 0.707106781
+	d[2*stride] = tmp13 + z1
+	d[6*stride] = tmp13 - z1
+
+	// 奇数部分
+	tmp10 = tmp4 + tmp5
+	tmp11 = tmp5 + tmp6
+	tmp12 = tmp6 + tmp7
+	z5 := (tmp10 - tmp12) * 0.382683433
+	z2 := 0.541196100*tmp10 + z5
+	z4 := 1.306562965*tmp12 + z5
+	z3 := tmp11 * 0.707106781
+	z11, z13 := tmp7+z3, tmp7-z3
+	d[5*stride] = z13 + z2
+	d[3*stride] = z13 - z2
+	d[1*stride] = z11 + z4
+	d[7*stride] = z11 - z4
+}
+
+// 编码一个8x8块
+//
+//	@param	w		熵编码写入器
+//	@param	plane	平面数据
+//	@param	pw		平面宽度
+//	@param	ph		平面高度
+//	@param	x0		块左上角横坐标
+//	@param	y0		块左上角纵坐标
+//	@param	table	表编号（0为亮度，1为色度）
+//	@param	pred	DC预测值
+func (p *jpegBlockEncoder) encodeBlock(w *jpegWriter, plane []byte, pw, ph, x0, y0, table int, pred *int) {
+	// 取块数据（超出边界时复制边缘像素）并做电平偏移
+	var blk [64]float32
+	for y := 0; y < 8; y++ {
+		sy := y0 + y
+		if sy >= ph {
+			sy = ph - 1
+		}
+		row := plane[sy*pw:]
+		for x := 0; x < 8; x++ {
+			sx := x0 + x
+			if sx >= pw {
+				sx = pw - 1
+			}
+			blk[y*8+x] = float32(row[sx]) - 128
+		}
+	}
+
+	// 二维DCT
+	for i := 0; i < 8; i++ {
+		jpegFDCT1D(blk[i*8:], 1)
+	}
+	for i := 0; i < 8; i++ {
+		jpegFDCT1D(blk[i:], 8)
+	}
+
+	// 量化（按Z字形顺序）
+	var coef [64]int
+	for i, n := range jpegZigzag {
+		v := blk[n] * p.divisor[table][n]
+		if v < 0 {
+			coef[i] = int(v - 0.5)
+		} else {
+			coef[i] = int(v + 0.5)
+		}
+	}
+
+	// DC系数差分编码
+	dc, ac := &jpegStdHuffmanCodes[table*2], &jpegStdHuffmanCodes[table*2+1]
+	n, bits := jpegCategory(coef[0] - *pred)
+	*pred = coef[0]
+	w.writeSymbol(dc, n)
+	w.writeBits(bits, n)
+
+	// AC系数游程编码
+	run := 0
+	for i := 1; i < 64; i++ {
+		if coef[i] == 0 {
+			run++
+			continue
+		}
+		for run > 15 {
+			w.writeSymbol(ac, 0xf0)
+			run -= 16
+		}
+		n, bits := jpegCategory(coef[i])
+		w.writeSymbol(ac, byte(run<<4)|n)
+		w.writeBits(bits, n)
+		run = 0
+	}
+	if run > 0 {
+		w.writeSymbol(ac, 0x00)
+	}
+}
+
+// EncodeJPEG 将YUV帧直接编码为JPEG（不经过RGB转换）
+//
+// 支持打包YUV 4:2:2（如YUYV，编码为4:2:2采样）与NV12、NV21、I420、YV12（编码为4:2:0采样），
+// 输入按BT.601有限范围处理，输出为JFIF全范围
+//
+//	@param	w		输出
+//	@param	frame	YUV帧
+//	@param	opts	编码参数（为nil时使用默认参数）
+//	@return	异常信息
+func EncodeJPEG(w io.Writer, frame *Frame, opts *JpegEncodeOptions) error {
+	// 检查参数
+	if w == nil || frame == nil {
+		return errors.Join(ErrInvalidParam, errors.New("empty writer or frame"))
+	}
+	quality, interval := DefaultJpegQuality, 0
+	if opts != nil {
+		if opts.Quality != 0 {
+			quality = opts.Quality
+		}
+		interval = opts.RestartInterval
+	}
+	if quality < 1 || quality > 100 {
+		return errors.Join(ErrInvalidParam, fmt.Errorf("quality %d out of range [1, 100]", quality))
+	}
+	if interval < 0 || interval > 0xffff {
+		return errors.Join(ErrInvalidParam, fmt.Errorf("restart interval %d out of range [0, 65535]", interval))
+	}
+
+	// 提取平面
+	planes, err := extractJpegPlanes(frame)
+	if err != nil {
+		return err
+	}
+	enc := newJpegBlockEncoder(quality)
+	jw := &jpegWriter{w: bufio.NewWriter(w)}
+
+	// 头部
+	jw.writeByte(0xff, false)
+	jw.writeByte(jpegMarkerSOI, false)
+	jw.writeSegment(jpegMarkerAPP0, []byte{'J', 'F', 'I', 'F', 0, 1, 1, 0, 0, 1, 0, 1, 0, 0})
+	jw.writeSegment(jpegMarkerDQT, append(append([]byte{0x00}, enc.quant[0][:]...), append([]byte{0x01}, enc.quant[1][:]...)...))
+	lumaSampling := byte(0x20 | planes.vSub)
+	jw.writeSegment(jpegMarkerSOF0, []byte{
+		8, byte(planes.h >> 8), byte(planes.h), byte(planes.w >> 8), byte(planes.w), 3,
+		1, lumaSampling, 0,
+		2, 0x11, 1,
+		3, 0x11, 1,
+	})
+	jw.writeSegment(jpegMarkerDHT, jpegStdDHTSegment[4:])
+	if interval > 0 {
+		jw.writeSegment(jpegMarkerDRI, []byte{byte(interval >> 8), byte(interval)})
+	}
+	jw.writeSegment(jpegMarkerSOS, []byte{3, 1, 0x00, 2, 0x11, 3, 0x11, 0, 63, 0})
+
+	// 逐MCU编码（每个MCU含2或4个亮度块与各1个色度块）
+	mcuW, mcuH := 16, 8*planes.vSub
+	mcus := 0
+	var predY, predCb, predCr int
+	for my := 0; my < (planes.h+mcuH-1)/mcuH; my++ {
+		for mx := 0; mx < (planes.w+mcuW-1)/mcuW; mx++ {
+			// 重启标记
+			if interval > 0 && mcus > 0 && mcus%interval == 0 {
+				jw.flushBits()
+				jw.writeByte(0xff, false)
+				jw.writeByte(byte(jpegMarkerRST0+(mcus/interval-1)%8), false)
+				predY, predCb, predCr = 0, 0, 0
+			}
+			mcus++
+
+			for v := 0; v < planes.vSub; v++ {
+				for h := 0; h < 2; h++ {
+					enc.encodeBlock(jw, planes.y, planes.w, planes.h, mx*mcuW+h*8, my*mcuH+v*8, 0, &predY)
+				}
+			}
+			enc.encodeBlock(jw, planes.cb, planes.cw, planes.ch, mx*8, my*8, 1, &predCb)
+			enc.encodeBlock(jw, planes.cr, planes.cw, planes.ch, mx*8, my*8, 1, &predCr)
+		}
+	}
+
+	// 结束
+	jw.flushBits()
+	jw.writeByte(0xff, false)
+	jw.writeByte(jpegMarkerEOI, false)
+	if jw.err == nil {
+		jw.err = jw.w.Flush()
+	}
+	if jw.err != nil {
+		return errors.Join(ErrEncodeJpegImageFailed, jw.err)
+	}
+	return nil
+}
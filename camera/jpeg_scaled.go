@@ -0,0 +1,522 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+)
+
+// JpegScale JPEG缩放解码比例（输出尺寸为原图的1/JpegScale）
+type JpegScale int
+
+const (
+	JpegScaleFull    JpegScale = 1 // 原尺寸
+	JpegScaleHalf    JpegScale = 2 // 1/2
+	JpegScaleQuarter JpegScale = 4 // 1/4
+	JpegScaleEighth  JpegScale = 8 // 1/8（仅使用DC系数）
+)
+
+// 缩小IDCT的系数表（[输出尺寸][像素][频率]，已包含归一化系数）
+var jpegReducedIDCT = func() (res [5][4][4]float32) {
+	for _, n := range []int{2, 4} {
+		for x := 0; x < n; x++ {
+			for u := 0; u < n; u++ {
+				c := 1.0
+				if u == 0 {
+					c = 1 / math.Sqrt2
+				}
+				res[n][x][u] = float32(0.5 * c * math.Cos(float64(2*x+1)*float64(u)*math.Pi/float64(2*n)))
+			}
+		}
+	}
+	return res
+}()
+
+// Huffman解码表
+type jpegHuffmanTable struct {
+	lut     [256]uint16 // 8位前缀查找表（码长<<8|符号值，0表示码长超过8位）
+	minCode [17]int32   // 各码长的最小码字
+	maxCode [17]int32   // 各码长的最大码字（-1表示该码长无码字）
+	valPtr  [17]int32   // 各码长首个码字在符号表中的下标
+	vals    []byte      // 符号值
+}
+
+// 解析一张Huffman表
+//
+//	@param	bits	各码长的码字数量（16个）
+//	@param	vals	符号值
+//	@return	Huffman解码表
+//	@return	异常信息（码字数量超出码长允许的范围或符号值不足）
+func newJpegHuffmanTable(bits, vals []byte) (*jpegHuffmanTable, error) {
+	t := &jpegHuffmanTable{vals: vals}
+	code, k := int32(0), int32(0)
+	for l := 1; l <= 16; l++ {
+		n := int32(bits[l-1])
+		if code+n > 1<<l || int(k+n) > len(vals) {
+			return nil, errors.New("invalid huffman table")
+		}
+		t.valPtr[l] = k
+		t.minCode[l] = code
+		t.maxCode[l] = -1
+		if n > 0 {
+			t.maxCode[l] = code + n - 1
+		}
+		// 码长不超过8位的码字填充查找表
+		if l <= 8 {
+			for i := int32(0); i < n; i++ {
+				prefix := (code + i) << (8 - l)
+				for j := int32(0); j < 1<<(8-l); j++ {
+					t.lut[prefix+j] = uint16(l)<<8 | uint16(vals[k+i])
+				}
+			}
+		}
+		code = (code + n) << 1
+		k += n
+	}
+	return t, nil
+}
+
+// 熵编码数据读取器
+type jpegBitReader struct {
+	data   []byte // JPEG数据
+	pos    int    // 读取位置
+	bits   uint32 // 位缓冲
+	n      uint   // 位缓冲中的有效位数
+	marker bool   // 是否已读到标记
+}
+
+// 填充位缓冲（遇到标记后以0填充）
+func (r *jpegBitReader) fill() {
+	for r.n <= 24 {
+		var b byte
+		if !r.marker && r.pos < len(r.data) {
+			b = r.data[r.pos]
+			if b == 0xff {
+				if r.pos+1 < len(r.data) && r.data[r.pos+1] == 0x00 {
+					r.pos += 2
+				} else {
+					r.marker, b = true, 0
+				}
+			} else {
+				r.pos++
+			}
+		}
+		r.bits |= uint32(b) << (24 - r.n)
+		r.n += 8
+	}
+}
+
+// 读取若干位
+func (r *jpegBitReader) getBits(n uint8) int32 {
+	if n == 0 {
+		return 0
+	}
+	r.fill()
+	v := int32(r.bits >> (32 - uint(n)))
+	r.bits <<= n
+	r.n -= uint(n)
+	return v
+}
+
+// 读取若干位并按JPEG规则扩展符号
+func (r *jpegBitReader) receiveExtend(n uint8) int32 {
+	v := r.getBits(n)
+	if n > 0 && v < 1<<(n-1) {
+		v += -1<<n + 1
+	}
+	return v
+}
+
+// 解码一个Huffman符号
+func (r *jpegBitReader) decodeHuffman(t *jpegHuffmanTable) (byte, error) {
+	r.fill()
+	if e := t.lut[r.bits>>24]; e != 0 {
+		l := uint(e >> 8)
+		r.bits <<= l
+		r.n -= l
+		return byte(e), nil
+	}
+	code := int32(r.bits >> 24)
+	r.bits <<= 8
+	r.n -= 8
+	for l := 9; l <= 16; l++ {
+		code = code<<1 | r.getBits(1)
+		if code <= t.maxCode[l] {
+			return t.vals[t.valPtr[l]+code-t.minCode[l]], nil
+		}
+	}
+	return 0, errors.New("invalid huffman code")
+}
+
+// 处理重启标记
+func (r *jpegBitReader) restart() error {
+	r.bits, r.n, r.marker = 0, 0, false
+	for r.pos < len(r.data) && r.data[r.pos] == 0xff && r.pos+1 < len(r.data) && r.data[r.pos+1] == 0xff {
+		r.pos++
+	}
+	if r.pos+1 >= len(r.data) || r.data[r.pos] != 0xff || r.data[r.pos+1] < jpegMarkerRST0 || r.data[r.pos+1] > jpegMarkerRST7 {
+		return fmt.Errorf("missing restart marker at offset %d", r.pos)
+	}
+	r.pos += 2
+	return nil
+}
+
+// 缩放解码的图像分量
+type jpegComponent struct {
+	id     byte              // 分量标识
+	h, v   int               // 水平、垂直采样因子
+	quant  *[64]int32        // 量化表（自然顺序）
+	dc, ac *jpegHuffmanTable // Huffman表
+	pred   int32             // DC预测值
+	plane  []byte            // 输出平面
+	stride int               // 输出平面行跨度
+}
+
+// 缩放解码器
+type jpegScaledDecoder struct {
+	width, height int                     // 原图尺寸
+	comps         []*jpegComponent        // 图像分量
+	quant         [4][64]int32            // 量化表
+	huffman       [2][4]*jpegHuffmanTable // Huffman表（[类型][编号]）
+	interval      int                     // 重启间隔
+}
+
+// 解析头部段
+func (d *jpegScaledDecoder) parseHeader(data []byte) (int, error) {
+	scan := -1
+	var err error
+	walkErr := walkJpegSegments(data, func(seg jpegSegment) bool {
+		// 跳过无长度字段的独立标记（TEM、RSTn）
+		if seg.size < 4 {
+			return true
+		}
+		payload := data[seg.offset+4 : seg.offset+seg.size]
+		switch seg.marker {
+		case jpegMarkerSOF0, jpegMarkerSOF0 + 1:
+			err = d.parseSOF(payload)
+		case jpegMarkerSOF0 + 2, jpegMarkerSOF0 + 3, jpegMarkerSOF0 + 5, jpegMarkerSOF0 + 6, jpegMarkerSOF0 + 7,
+			jpegMarkerSOF0 + 9, jpegMarkerSOF0 + 10, jpegMarkerSOF0 + 11, jpegMarkerSOF0 + 13, jpegMarkerSOF0 + 14, jpegMarkerSOF0 + 15:
+			err = fmt.Errorf("unsupported jpeg process SOF%d", seg.marker-jpegMarkerSOF0)
+		case jpegMarkerDQT:
+			err = d.parseDQT(payload)
+		case jpegMarkerDHT:
+			err = d.parseDHT(payload)
+		case jpegMarkerDRI:
+			if len(payload) != 2 {
+				err = errors.New("invalid DRI segment")
+			} else {
+				d.interval = int(payload[0])<<8 | int(payload[1])
+			}
+		case jpegMarkerSOS:
+			err = d.parseSOS(payload)
+			scan = seg.offset + seg.size
+		}
+		return err == nil
+	})
+	if walkErr != nil {
+		return 0, walkErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	if scan < 0 {
+		return 0, errors.New("missing SOS marker")
+	}
+	return scan, nil
+}
+
+// 解析帧头（SOF）
+func (d *jpegScaledDecoder) parseSOF(p []byte) error {
+	if len(p) < 6 || p[0] != 8 {
+		return errors.New("invalid SOF segment or unsupported precision")
+	}
+	d.height, d.width = int(p[1])<<8|int(p[2]), int(p[3])<<8|int(p[4])
+	n := int(p[5])
+	if d.width == 0 || d.height == 0 || (n != 1 && n != 3) || len(p) < 6+3*n {
+		return errors.New("invalid SOF segment")
+	}
+	d.comps = make([]*jpegComponent, n)
+	for i := range d.comps {
+		c := p[6+3*i:]
+		if c[2] > 3 {
+			return errors.New("invalid quantization table id")
+		}
+		d.comps[i] = &jpegComponent{id: c[0], h: int(c[1] >> 4), v: int(c[1] & 0x0f), quant: &d.quant[c[2]]}
+		if d.comps[i].h < 1 || d.comps[i].h > 4 || d.comps[i].v < 1 || d.comps[i].v > 4 {
+			return errors.New("invalid sampling factor")
+		}
+	}
+	return nil
+}
+
+// 解析量化表（DQT）
+func (d *jpegScaledDecoder) parseDQT(p []byte) error {
+	for len(p) > 0 {
+		precision, id := p[0]>>4, p[0]&0x0f
+		size := 64 << precision
+		if id > 3 || precision > 1 || len(p) < 1+size {
+			return errors.New("invalid DQT segment")
+		}
+		for i, n := range jpegZigzag {
+			if precision == 0 {
+				d.quant[id][n] = int32(p[1+i])
+			} else {
+				d.quant[id][n] = int32(p[1+2*i])<<8 | int32(p[2+2*i])
+			}
+		}
+		p = p[1+size:]
+	}
+	return nil
+}
+
+// 解析Huffman表（DHT）
+func (d *jpegScaledDecoder) parseDHT(p []byte) error {
+	for len(p) > 0 {
+		if len(p) < 17 {
+			return errors.New("invalid DHT segment")
+		}
+		class, id := p[0]>>4, p[0]&0x0f
+		total := 0
+		for _, n := range p[1:17] {
+			total += int(n)
+		}
+		if class > 1 || id > 3 || total > 256 || len(p) < 17+total {
+			return errors.New("invalid DHT segment")
+		}
+		t, err := newJpegHuffmanTable(p[1:17], p[17:17+total])
+		if err != nil {
+			return errors.Join(ErrDecodeJpegImageFailed, err)
+		}
+		d.huffman[class][id] = t
+		p = p[17+total:]
+	}
+	return nil
+}
+
+// 解析扫描头（SOS）
+func (d *jpegScaledDecoder) parseSOS(p []byte) error {
+	if d.comps == nil {
+		return errors.New("SOS before SOF")
+	}
+	if len(p) < 1 || int(p[0]) != len(d.comps) || len(p) < 1+2*len(d.comps)+3 {
+		return errors.New("unsupported non-interleaved or invalid scan")
+	}
+
+	// 缺少Huffman表时使用标准表
+	for _, t := range jpegStdHuffmanTables {
+		if d.huffman[t[0]>>4][t[0]&0x0f] == nil {
+			d.huffman[t[0]>>4][t[0]&0x0f], _ = newJpegHuffmanTable(t[1:17], t[17:])
+		}
+	}
+
+	for i, c := range d.comps {
+		s := p[1+2*i:]
+		if s[0] != c.id || s[1]>>4 > 3 || s[1]&0x0f > 3 {
+			return errors.New("invalid scan component")
+		}
+		c.dc, c.ac = d.huffman[0][s[1]>>4], d.huffman[1][s[1]&0x0f]
+		if c.dc == nil || c.ac == nil {
+			return fmt.Errorf("scan component %d references undefined huffman table", c.id)
+		}
+	}
+	return nil
+}
+
+// 解码一个8x8块并以缩小的IDCT输出n×n像素
+func (d *jpegScaledDecoder) decodeBlock(r *jpegBitReader, c *jpegComponent, n int, dst []byte, stride int) error {
+	// DC系数
+	s, err := r.decodeHuffman(c.dc)
+	if err != nil {
+		return err
+	}
+	if s > 11 {
+		return errors.New("invalid DC coefficient size")
+	}
+	c.pred += r.receiveExtend(s)
+
+	// AC系数（仅保留左上角n×n个低频系数，其余解码后丢弃）
+	var coef [64]int32
+	coef[0] = c.pred * c.quant[0]
+	for k := 1; k < 64; k++ {
+		rs, err := r.decodeHuffman(c.ac)
+		if err != nil {
+			return err
+		}
+		run, size := int(rs>>4), rs&0x0f
+		if size == 0 {
+			if run != 15 {
+				break
+			}
+			k += 15
+			continue
+		}
+		k += run
+		if k > 63 {
+			return errors.New("AC coefficient index out of range")
+		}
+		v := r.receiveExtend(size)
+		if idx := jpegZigzag[k]; idx/8 < n && idx%8 < n {
+			coef[idx] = v * c.quant[idx]
+		}
+	}
+
+	// 1/8仅使用DC系数
+	if n == 1 {
+		dst[0] = clampUint8((coef[0]+4)>>3 + 128)
+		return nil
+	}
+
+	// 可分离的缩小IDCT
+	t := &jpegReducedIDCT[n]
+	var tmp [4][4]float32
+	for v := 0; v < n; v++ {
+		for x := 0; x < n; x++ {
+			var sum float32
+			for u := 0; u < n; u++ {
+				sum += t[x][u] * float32(coef[v*8+u])
+			}
+			tmp[v][x] = sum
+		}
+	}
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			var sum float32
+			for v := 0; v < n; v++ {
+				sum += t[y][v] * tmp[v][x]
+			}
+			dst[y*stride+x] = clampUint8(int32(math.Floor(float64(sum)+0.5)) + 128)
+		}
+	}
+	return nil
+}
+
+// 解码扫描数据
+func (d *jpegScaledDecoder) decodeScan(data []byte, n int) error {
+	// MCU尺寸
+	hmax, vmax := 1, 1
+	for _, c := range d.comps {
+		if c.h > hmax {
+			hmax = c.h
+		}
+		if c.v > vmax {
+			vmax = c.v
+		}
+	}
+	if len(d.comps) == 1 {
+		d.comps[0].h, d.comps[0].v, hmax, vmax = 1, 1, 1, 1
+	}
+	mcusX := (d.width + 8*hmax - 1) / (8 * hmax)
+	mcusY := (d.height + 8*vmax - 1) / (8 * vmax)
+
+	// 分配输出平面（按MCU对齐）
+	for _, c := range d.comps {
+		c.stride = mcusX * c.h * n
+		c.plane = make([]byte, c.stride*mcusY*c.v*n)
+	}
+
+	// 逐MCU解码
+	r := &jpegBitReader{data: data}
+	for my := 0; my < mcusY; my++ {
+		for mx := 0; mx < mcusX; mx++ {
+			if mcu := my*mcusX + mx; d.interval > 0 && mcu > 0 && mcu%d.interval == 0 {
+				if err := r.restart(); err != nil {
+					return err
+				}
+				for _, c := range d.comps {
+					c.pred = 0
+				}
+			}
+			for _, c := range d.comps {
+				for bv := 0; bv < c.v; bv++ {
+					for bh := 0; bh < c.h; bh++ {
+						x, y := (mx*c.h+bh)*n, (my*c.v+bv)*n
+						if err := d.decodeBlock(r, c, n, c.plane[y*c.stride+x:], c.stride); err != nil {
+							return fmt.Errorf("mcu (%d, %d): %w", mx, my, err)
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// 根据采样因子获取YCbCr子采样比例
+func (d *jpegScaledDecoder) subsampleRatio() (image.YCbCrSubsampleRatio, error) {
+	y, cb, cr := d.comps[0], d.comps[1], d.comps[2]
+	if cb.h != 1 || cb.v != 1 || cr.h != 1 || cr.v != 1 {
+		return 0, fmt.Errorf("unsupported chroma sampling %dx%d", cb.h, cb.v)
+	}
+	switch [2]int{y.h, y.v} {
+	case [2]int{1, 1}:
+		return image.YCbCrSubsampleRatio444, nil
+	case [2]int{2, 1}:
+		return image.YCbCrSubsampleRatio422, nil
+	case [2]int{2, 2}:
+		return image.YCbCrSubsampleRatio420, nil
+	case [2]int{1, 2}:
+		return image.YCbCrSubsampleRatio440, nil
+	case [2]int{4, 1}:
+		return image.YCbCrSubsampleRatio411, nil
+	case [2]int{4, 2}:
+		return image.YCbCrSubsampleRatio410, nil
+	default:
+		return 0, fmt.Errorf("unsupported luma sampling %dx%d", y.h, y.v)
+	}
+}
+
+// DecodeMJPEGScaled 按比例缩小解码MJPEG帧（在DCT域缩放，缺少Huffman表时自动注入标准表）
+//
+// 1/2、1/4仅对低频系数做缩小的IDCT，1/8直接使用DC系数，跳过了完整IDCT与后续缩放，
+// 适合缩略图、预览及运动检测等场景，仅支持基线（顺序）JPEG
+//
+//	@param	data	MJPEG帧数据
+//	@param	scale	缩放比例
+//	@return	YCbCr图像（尺寸为原图除以缩放比例后向上取整）
+//	@return	异常信息
+func DecodeMJPEGScaled(data []byte, scale JpegScale) (*image.YCbCr, error) {
+	switch scale {
+	case JpegScaleFull:
+		return DecodeMJPEG(data)
+	case JpegScaleHalf, JpegScaleQuarter, JpegScaleEighth:
+	default:
+		return nil, errors.Join(ErrInvalidParam, fmt.Errorf("unsupported jpeg scale 1/%d", scale))
+	}
+	n := 8 / int(scale)
+
+	// 解析头部并解码扫描数据
+	d := &jpegScaledDecoder{}
+	scan, err := d.parseHeader(data)
+	if err != nil {
+		if errors.Is(err, ErrDecodeJpegImageFailed) {
+			return nil, err
+		}
+		return nil, errors.Join(ErrDecodeJpegImageFailed, err)
+	}
+	if err := d.decodeScan(data[scan:], n); err != nil {
+		return nil, errors.Join(ErrDecodeJpegImageFailed, err)
+	}
+	rect := image.Rect(0, 0, (d.width+int(scale)-1)/int(scale), (d.height+int(scale)-1)/int(scale))
+
+	// 灰度图像
+	if len(d.comps) == 1 {
+		c := d.comps[0]
+		return grayToYCbCr(&image.Gray{Pix: c.plane, Stride: c.stride, Rect: rect}), nil
+	}
+
+	// 彩色图像
+	ratio, err := d.subsampleRatio()
+	if err != nil {
+		return nil, errors.Join(ErrDecodeJpegImageFailed, err)
+	}
+	return &image.YCbCr{
+		Y:              d.comps[0].plane,
+		Cb:             d.comps[1].plane,
+		Cr:             d.comps[2].plane,
+		YStride:        d.comps[0].stride,
+		CStride:        d.comps[1].stride,
+		SubsampleRatio: ratio,
+		Rect:           rect,
+	}, nil
+}
@@ -1,8 +1,25 @@
 package camera
 
+import (
+	"context"
+	"time"
+)
+
 const (
 	// 获取帧失败重试次数
 	GetFrameRetryCount = 50
+	// 损坏帧重新获取次数
+	CorruptFrameRetryCount = 3
+)
+
+// CorruptFramePolicy 损坏帧处理策略
+type CorruptFramePolicy int
+
+const (
+	CorruptFramePass  CorruptFramePolicy = iota // 放行：与旧版一样正常返回帧数据，仅计入FrameStats.CorruptedFrames
+	CorruptFrameMark                            // 标记：返回帧数据，同时返回ErrFrameCorrupted
+	CorruptFrameRetry                           // 重试：丢弃损坏帧并重新获取，超过重试次数后返回ErrFrameCorrupted
+	CorruptFrameDrop                            // 丢弃：不返回帧数据，仅返回ErrFrameCorrupted
 )
 
 // Manager 相机管理器
@@ -13,16 +30,27 @@ type Manager interface {
 	//	@return 错误信息
 	GetList() (DeviceList, error)
 
+	// Watch 监听相机接入、移除与变化，缓存的相机列表随事件自动更新
+	// （Linux下监听/dev的inotify事件，其它平台或监听失败时每DefaultWatchInterval轮询一次）
+	//
+	//	@param	ctx	上下文（取消后停止监听并关闭事件通道）
+	//	@return	事件通道
+	//	@return	异常信息
+	Watch(ctx context.Context) (<-chan DeviceEvent, error)
+
 	// GetDeviceWithID 通过相机ID获取缓存的相机信息
 	//
-	//	@param	id	相机ID
+	//	@param	id	相机ID或别名
 	//	@return	缓存的相机信息
 	//	@return	异常信息
 	GetDeviceWithID(id string) (*Device, error)
 
 	// GetDeviceConfigInfo 通过相机ID获取设备的配置信息
 	//
-	//	@param	id	相机ID
+	// 注意：相机库（VideoFrameInfo.fps）目前只提供整数帧率，29.97与30、59.94与60等模式会被合并为
+	// 同一个整数帧率的配置（已去重），返回的Interval均为1/FPS，分数帧间隔无法与该列表精确匹配
+	//
+	//	@param	id	相机ID或别名
 	//	@return	设备配置信息
 	//	@return	异常信息
 	GetDeviceConfigInfo(id string) (DeviceConfigList, error)
@@ -34,13 +62,40 @@ type Manager interface {
 	//	@return	异常信息
 	GetCurrDeviceConfigInfo() (*Device, *DeviceConfig, error)
 
-	// Open 打开相机
+	// Open 打开相机（配置需在GetDeviceConfigInfo返回的列表中，相机库目前只支持整数帧率，
+	// 分数帧间隔的配置会返回ErrDeviceMediaConfigNotFound）
 	//
-	//	@param	id		相机ID
+	//	@param	id		相机ID或别名
 	//	@param	info	分辨率信息
 	//	@return	异常信息
 	Open(id string, info DeviceConfig) error
 
+	// OpenBest 按评分顺序依次尝试打开候选配置，并在超时时间内确认能收到有效帧，
+	// 失败时回退到下一个候选配置（部分相机会声明实际无法出流的配置）
+	//
+	//	@param	id			相机ID或别名
+	//	@param	selector	配置选择器
+	//	@param	timeout		等待有效帧的超时时间（为0时使用DefaultOpenFrameTimeout）
+	//	@return	打开结果（包含最终使用的配置及其它候选配置的失败原因）
+	//	@return	异常信息
+	OpenBest(id string, selector *ConfigSelector, timeout time.Duration) (*OpenResult, error)
+
+	// Reconfigure 在不关闭相机的情况下切换配置（不重新枚举设备，订阅者与取帧统计保留），
+	// 切换成功后通知配置变更订阅者，失败时尝试恢复原配置
+	//
+	//	@param	cfg	新配置（必须在当前相机的配置列表中）
+	//	@return	异常信息
+	Reconfigure(cfg DeviceConfig) error
+
+	// SubscribeConfig 订阅配置变更（订阅在关闭相机后仍然有效）
+	//
+	//	@param	fn	回调函数
+	//	@return	取消订阅函数
+	SubscribeConfig(fn func(ConfigChange)) func()
+
+	// GetFrameStats 获取当前相机的取帧统计
+	GetFrameStats() FrameStats
+
 	// GetStream 获取帧
 	//
 	//	@return	帧数据
@@ -48,6 +103,30 @@ type Manager interface {
 	//	@return	异常信息
 	GetFrame() ([]byte, *DeviceConfig, error)
 
+	// GetStreamCache 获取压缩码流（H264、HEVC、VP8）从最近关键帧开始的缓存帧，
+	// 首帧为携带参数集的关键帧，供中途接入的消费者在实时帧之前使用
+	//
+	//	@return	缓存帧列表（尚未收到关键帧时为空）
+	//	@return	异常信息
+	GetStreamCache() ([]*Frame, error)
+
+	// SetCorruptFramePolicy 设置损坏帧处理策略（默认为CorruptFramePass）
+	//
+	//	@param	policy	损坏帧处理策略
+	SetCorruptFramePolicy(policy CorruptFramePolicy)
+
+	// SetListNodeTypes 设置GetList返回的节点类型（默认仅返回NodeCapture，
+	// 元数据、输出与内存到内存节点无法作为相机打开）
+	//
+	//	@param	types	节点类型（可按位组合，如NodeCapture|NodeMetadata，为0时恢复默认值）
+	SetListNodeTypes(types DeviceNodeType)
+
+	// SetDeviceAliases 设置设备别名，设置后所有接收相机ID的方法也接受别名
+	//
+	//	@param	aliases	设备别名列表（为nil时清除）
+	//	@return	异常信息（别名规则无效时返回ErrInvalidParam）
+	SetDeviceAliases(aliases *DeviceAliases) error
+
 	// Close 关闭已打开的相机
 	Close()
 
@@ -0,0 +1,126 @@
+package camera
+
+import (
+	"bytes"
+	"errors"
+)
+
+// Annex-B起始码
+var annexBStartCode = []byte{0x00, 0x00, 0x01}
+
+// SplitAnnexB 按起始码拆分Annex-B字节流
+//
+//	@param	data	Annex-B字节流（00 00 01或00 00 00 01分隔）
+//	@return	NAL单元列表（不含起始码，与原数据共享内存）
+func SplitAnnexB(data []byte) [][]byte {
+	var res [][]byte
+	start := -1
+	for pos := 0; ; {
+		i := bytes.Index(data[pos:], annexBStartCode)
+		end := len(data)
+		if i >= 0 {
+			end = pos + i
+		}
+		// 保存上一个NAL单元（去除尾部的0字节，包括4字节起始码的首字节）
+		if start >= 0 {
+			nal := data[start:end]
+			for len(nal) > 0 && nal[len(nal)-1] == 0x00 {
+				nal = nal[:len(nal)-1]
+			}
+			if len(nal) > 0 {
+				res = append(res, nal)
+			}
+		}
+		if i < 0 {
+			return res
+		}
+		start = end + len(annexBStartCode)
+		pos = start
+	}
+}
+
+// 去除NAL单元中的防竞争字节（00 00 03 → 00 00）
+//
+//	@param	nal	NAL单元
+//	@return	原始字节序列载荷（RBSP）
+func unescapeRBSP(nal []byte) []byte {
+	if bytes.Index(nal, []byte{0x00, 0x00, 0x03}) < 0 {
+		return nal
+	}
+	res := make([]byte, 0, len(nal))
+	zeros := 0
+	for _, b := range nal {
+		if zeros >= 2 && b == 0x03 {
+			zeros = 0
+			continue
+		}
+		if b == 0x00 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+		res = append(res, b)
+	}
+	return res
+}
+
+// 码流位读取器（出错后后续读取均返回0，调用方在最后统一检查err）
+type bitstreamReader struct {
+	data []byte // 数据
+	pos  int    // 位位置
+	err  error  // 首个读取错误
+}
+
+// 读取n位无符号整数（n不超过32）
+func (r *bitstreamReader) u(n int) uint32 {
+	if r.err != nil {
+		return 0
+	}
+	if r.pos+n > len(r.data)*8 {
+		r.err = errors.New("unexpected end of bitstream")
+		return 0
+	}
+	var v uint32
+	for i := 0; i < n; i++ {
+		v = v<<1 | uint32(r.data[r.pos>>3]>>(7-r.pos&7)&1)
+		r.pos++
+	}
+	return v
+}
+
+// 读取1位标志
+func (r *bitstreamReader) flag() bool {
+	return r.u(1) == 1
+}
+
+// 跳过n位
+func (r *bitstreamReader) skip(n int) {
+	for ; n > 32; n -= 32 {
+		r.u(32)
+	}
+	r.u(n)
+}
+
+// 读取无符号指数哥伦布编码
+func (r *bitstreamReader) ue() uint32 {
+	zeros := 0
+	for !r.flag() {
+		if r.err != nil {
+			return 0
+		}
+		if zeros++; zeros > 31 {
+			r.err = errors.New("invalid exp-golomb code")
+			return 0
+		}
+	}
+	return 1<<zeros - 1 + r.u(zeros)
+}
+
+// 读取有符号指数哥伦布编码
+func (r *bitstreamReader) se() int32 {
+	v := r.ue()
+	if v&1 == 1 {
+		return int32(v/2 + 1)
+	}
+	return -int32(v / 2)
+}
@@ -0,0 +1,68 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultOpenFrameTimeout 打开相机后等待有效帧的默认超时时间
+const DefaultOpenFrameTimeout = 3 * time.Second
+
+// OpenAttempt 单个候选配置的打开记录
+type OpenAttempt struct {
+	Candidate *ConfigCandidate // 候选配置（含评分说明）
+	Err       error            // 打开或验证失败的原因（成功时为nil）
+	Duration  time.Duration    // 尝试耗时
+}
+
+// OpenResult 按候选配置依次打开相机的结果
+type OpenResult struct {
+	Config   *DeviceConfig  // 最终使用的配置（全部失败时为nil）
+	Attempts []*OpenAttempt // 按尝试顺序排列的打开记录
+}
+
+// String 打开过程说明
+func (p *OpenResult) String() string {
+	var b strings.Builder
+	if p.Config != nil {
+		fmt.Fprintf(&b, "opened %s", p.Config)
+	} else {
+		b.WriteString("no config could be opened")
+	}
+	for i, v := range p.Attempts {
+		status := "ok"
+		if v.Err != nil {
+			status = v.Err.Error()
+		}
+		fmt.Fprintf(&b, "\n%d. %s in %s: %s", i+1, v.Candidate.Config, v.Duration.Round(time.Millisecond), status)
+	}
+	return b.String()
+}
+
+// OpenWithFallback 依次尝试打开候选配置，直到有一个成功
+//
+//	@param	candidates	排序后的候选配置
+//	@param	open		打开并验证配置的函数（返回nil表示成功）
+//	@return	打开结果
+//	@return	异常信息（全部失败时包含ErrDeviceOpenFailed与每个候选配置的失败原因）
+func OpenWithFallback(candidates []*ConfigCandidate, open func(cfg DeviceConfig) error) (*OpenResult, error) {
+	if len(candidates) == 0 {
+		return &OpenResult{}, ErrDeviceMediaConfigNotFound
+	}
+
+	res := &OpenResult{}
+	errs := []error{ErrDeviceOpenFailed}
+	for _, candidate := range candidates {
+		start := time.Now()
+		err := open(*candidate.Config)
+		res.Attempts = append(res.Attempts, &OpenAttempt{Candidate: candidate, Err: err, Duration: time.Since(start)})
+		if err == nil {
+			res.Config = candidate.Config.Clone()
+			return res, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", candidate.Config, err))
+	}
+	return res, errors.Join(errs...)
+}
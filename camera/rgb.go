@@ -0,0 +1,237 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"runtime"
+)
+
+// 位域（通道在像素字中的位置）
+type bitField struct {
+	shift uint // 起始位
+	bits  uint // 位数（0表示不存在该通道）
+}
+
+// 读取位域并扩展到8位
+func (f bitField) get(word uint32) uint8 {
+	full := uint32(1)<<f.bits - 1
+	return uint8((((word>>f.shift)&full)*255 + full/2) / full)
+}
+
+// 位紧凑RGB格式布局（1、2或4字节组成一个像素字）
+type packedRGBLayout struct {
+	bytes     int      // 每像素字节数
+	bigEndian bool     // 像素字是否为大端序
+	r, g, b   bitField // 颜色通道
+	a         bitField // 透明通道
+}
+
+// 字节对齐RGB格式布局（每个通道占用一个字节）
+type byteRGBLayout struct {
+	bytes   int // 每像素字节数
+	r, g, b int // 颜色通道偏移
+	a       int // 透明通道偏移（-1表示不存在）
+}
+
+// 位紧凑RGB格式布局映射
+var packedRGBLayouts = map[Fourcc]packedRGBLayout{
+	FOURCC_RGB332:  {bytes: 1, r: bitField{5, 3}, g: bitField{2, 3}, b: bitField{0, 2}},
+	FOURCC_RGB444:  {bytes: 2, r: bitField{8, 4}, g: bitField{4, 4}, b: bitField{0, 4}},
+	FOURCC_ARGB444: {bytes: 2, r: bitField{8, 4}, g: bitField{4, 4}, b: bitField{0, 4}, a: bitField{12, 4}},
+	FOURCC_XRGB444: {bytes: 2, r: bitField{8, 4}, g: bitField{4, 4}, b: bitField{0, 4}},
+	FOURCC_RGBA444: {bytes: 2, r: bitField{12, 4}, g: bitField{8, 4}, b: bitField{4, 4}, a: bitField{0, 4}},
+	FOURCC_RGBX444: {bytes: 2, r: bitField{12, 4}, g: bitField{8, 4}, b: bitField{4, 4}},
+	FOURCC_ABGR444: {bytes: 2, r: bitField{0, 4}, g: bitField{4, 4}, b: bitField{8, 4}, a: bitField{12, 4}},
+	FOURCC_XBGR444: {bytes: 2, r: bitField{0, 4}, g: bitField{4, 4}, b: bitField{8, 4}},
+	FOURCC_BGRA444: {bytes: 2, r: bitField{4, 4}, g: bitField{8, 4}, b: bitField{12, 4}, a: bitField{0, 4}},
+	FOURCC_BGRX444: {bytes: 2, r: bitField{4, 4}, g: bitField{8, 4}, b: bitField{12, 4}},
+	FOURCC_RGB555:  {bytes: 2, r: bitField{10, 5}, g: bitField{5, 5}, b: bitField{0, 5}},
+	FOURCC_ARGB555: {bytes: 2, r: bitField{10, 5}, g: bitField{5, 5}, b: bitField{0, 5}, a: bitField{15, 1}},
+	FOURCC_XRGB555: {bytes: 2, r: bitField{10, 5}, g: bitField{5, 5}, b: bitField{0, 5}},
+	FOURCC_RGBA555: {bytes: 2, r: bitField{11, 5}, g: bitField{6, 5}, b: bitField{1, 5}, a: bitField{0, 1}},
+	FOURCC_RGBX555: {bytes: 2, r: bitField{11, 5}, g: bitField{6, 5}, b: bitField{1, 5}},
+	FOURCC_ABGR555: {bytes: 2, r: bitField{0, 5}, g: bitField{5, 5}, b: bitField{10, 5}, a: bitField{15, 1}},
+	FOURCC_XBGR555: {bytes: 2, r: bitField{0, 5}, g: bitField{5, 5}, b: bitField{10, 5}},
+	FOURCC_BGRA555: {bytes: 2, r: bitField{1, 5}, g: bitField{6, 5}, b: bitField{11, 5}, a: bitField{0, 1}},
+	FOURCC_BGRX555: {bytes: 2, r: bitField{1, 5}, g: bitField{6, 5}, b: bitField{11, 5}},
+	FOURCC_RGB565:  {bytes: 2, r: bitField{11, 5}, g: bitField{5, 6}, b: bitField{0, 5}},
+	FOURCC_RGB555X: {bytes: 2, bigEndian: true, r: bitField{10, 5}, g: bitField{5, 5}, b: bitField{0, 5}},
+	FOURCC_RGB565X: {bytes: 2, bigEndian: true, r: bitField{11, 5}, g: bitField{5, 6}, b: bitField{0, 5}},
+	FOURCC_BGR666:  {bytes: 4, bigEndian: true, r: bitField{14, 6}, g: bitField{20, 6}, b: bitField{26, 6}},
+}
+
+// 字节对齐RGB格式布局映射
+var byteRGBLayouts = map[Fourcc]byteRGBLayout{
+	FOURCC_BGR24:  {bytes: 3, r: 2, g: 1, b: 0, a: -1},
+	FOURCC_RGB24:  {bytes: 3, r: 0, g: 1, b: 2, a: -1},
+	FOURCC_BGR32:  {bytes: 4, r: 2, g: 1, b: 0, a: -1},
+	FOURCC_ABGR32: {bytes: 4, r: 2, g: 1, b: 0, a: 3},
+	FOURCC_XBGR32: {bytes: 4, r: 2, g: 1, b: 0, a: -1},
+	FOURCC_BGRA32: {bytes: 4, r: 3, g: 2, b: 1, a: 0},
+	FOURCC_BGRX32: {bytes: 4, r: 3, g: 2, b: 1, a: -1},
+	FOURCC_RGB32:  {bytes: 4, r: 1, g: 2, b: 3, a: -1},
+	FOURCC_RGBA32: {bytes: 4, r: 0, g: 1, b: 2, a: 3},
+	FOURCC_RGBX32: {bytes: 4, r: 0, g: 1, b: 2, a: -1},
+	FOURCC_ARGB32: {bytes: 4, r: 1, g: 2, b: 3, a: 0},
+	FOURCC_XRGB32: {bytes: 4, r: 1, g: 2, b: 3, a: -1},
+}
+
+// IsRGBFormat 是否为可转换的RGB格式
+//
+//	@param	format	帧格式
+//	@return	是否为RGB格式
+func IsRGBFormat(format Fourcc) bool {
+	if _, ok := packedRGBLayouts[format]; ok {
+		return true
+	}
+	_, ok := byteRGBLayouts[format]
+	return ok
+}
+
+// RGBOrientation RGB帧的行顺序
+type RGBOrientation int
+
+const (
+	RGBOrientationAuto     RGBOrientation = iota // 按采集平台推测（见IsBottomUp）
+	RGBOrientationTopDown                        // 首行为图像顶部
+	RGBOrientationBottomUp                       // 首行为图像底部
+)
+
+// IsBottomUp 按采集平台推测RGB帧是否为自下而上存储（启发式判断，并非逐帧检测）
+//
+// 采集后端不会上报帧的行顺序，此处仅依据经验推测：DirectShow以DIB形式返回的
+// RGB24/BGR24帧首行为图像底部，其余格式及V4L2始终视为自上而下。
+// 推测不准确时，请通过Frame.Orientation或DecodeRGBAWithOrientation显式指定行顺序
+//
+//	@param	format	帧格式
+//	@return	是否需要垂直翻转
+func IsBottomUp(format Fourcc) bool {
+	return runtime.GOOS == "windows" && (format == FOURCC_RGB24 || format == FOURCC_BGR24)
+}
+
+// DecodeRGBA 将RGB系列格式的帧转换为RGBA图像（行顺序自动判断）
+//
+//	@param	data	帧数据
+//	@param	info	帧信息
+//	@return	RGBA图像
+//	@return	异常信息
+func DecodeRGBA(data []byte, info *DeviceConfig) (*image.RGBA, error) {
+	return DecodeRGBAWithOrientation(data, info, RGBOrientationAuto)
+}
+
+// DecodeRGBAWithOrientation 以指定的行顺序将RGB系列格式的帧转换为RGBA图像
+//
+// 带透明通道的格式会转换为预乘透明度的颜色值，填充位会被忽略并视为不透明
+//
+//	@param	data	帧数据
+//	@param	info	帧信息
+//	@param	orient	行顺序
+//	@return	RGBA图像
+//	@return	异常信息
+func DecodeRGBAWithOrientation(data []byte, info *DeviceConfig, orient RGBOrientation) (*image.RGBA, error) {
+	return decodeRGBA(data, info, orient, putPremultiplied)
+}
+
+// 将RGB系列格式的帧转换为RGBA像素
+//
+//	@param	data	帧数据
+//	@param	info	帧信息
+//	@param	orient	行顺序
+//	@param	put		像素写入函数（决定是否预乘透明度）
+//	@return	RGBA图像
+//	@return	异常信息
+func decodeRGBA(data []byte, info *DeviceConfig, orient RGBOrientation, put func(dst []byte, r, g, b, a uint8)) (*image.RGBA, error) {
+	// 获取像素布局
+	if info == nil {
+		return nil, errors.Join(ErrFrameSizeMismatch, errors.New("empty frame size"))
+	}
+	packed, isPacked := packedRGBLayouts[info.Format]
+	byteLayout, isByte := byteRGBLayouts[info.Format]
+	if !isPacked && !isByte {
+		return nil, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q is not rgb", info.Format))
+	}
+
+	// 计算行跨度（DIB的行尾会填充到4字节对齐）
+	bpp := byteLayout.bytes
+	if isPacked {
+		bpp = packed.bytes
+	}
+	width, height := int(info.Width), int(info.Height)
+	stride, err := frameStride(data, info, width*bpp)
+	if err != nil {
+		return nil, err
+	}
+
+	// 确认行顺序
+	bottomUp := orient == RGBOrientationBottomUp ||
+		(orient == RGBOrientationAuto && IsBottomUp(info.Format))
+
+	// 逐行转换
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		srcY := y
+		if bottomUp {
+			srcY = height - 1 - y
+		}
+		src := data[srcY*stride : srcY*stride+width*bpp]
+		dst := img.Pix[y*img.Stride : y*img.Stride+width*4]
+		if isPacked {
+			packed.convertRow(dst, src, width, put)
+		} else {
+			byteLayout.convertRow(dst, src, width, put)
+		}
+	}
+
+	// OK
+	return img, nil
+}
+
+// 转换一行位紧凑RGB像素
+func (l packedRGBLayout) convertRow(dst, src []byte, width int, put func(dst []byte, r, g, b, a uint8)) {
+	for x := 0; x < width; x++ {
+		// 读取像素字
+		var word uint32
+		px := src[x*l.bytes : x*l.bytes+l.bytes]
+		for i := range px {
+			if l.bigEndian {
+				word = word<<8 | uint32(px[i])
+			} else {
+				word |= uint32(px[i]) << (8 * i)
+			}
+		}
+		// 提取通道
+		a := uint8(0xff)
+		if l.a.bits > 0 {
+			a = l.a.get(word)
+		}
+		put(dst[4*x:4*x+4], l.r.get(word), l.g.get(word), l.b.get(word), a)
+	}
+}
+
+// 转换一行字节对齐RGB像素
+func (l byteRGBLayout) convertRow(dst, src []byte, width int, put func(dst []byte, r, g, b, a uint8)) {
+	for x := 0; x < width; x++ {
+		px := src[x*l.bytes : x*l.bytes+l.bytes]
+		a := uint8(0xff)
+		if l.a >= 0 {
+			a = px[l.a]
+		}
+		put(dst[4*x:4*x+4], px[l.r], px[l.g], px[l.b], a)
+	}
+}
+
+// 以预乘透明度的形式写入RGBA像素
+func putPremultiplied(dst []byte, r, g, b, a uint8) {
+	if a != 0xff {
+		r = uint8((uint32(r)*uint32(a) + 127) / 255)
+		g = uint8((uint32(g)*uint32(a) + 127) / 255)
+		b = uint8((uint32(b)*uint32(a) + 127) / 255)
+	}
+	dst[0], dst[1], dst[2], dst[3] = r, g, b, a
+}
+
+// 以非预乘透明度的形式写入RGBA像素
+func putStraight(dst []byte, r, g, b, a uint8) {
+	dst[0], dst[1], dst[2], dst[3] = r, g, b, a
+}
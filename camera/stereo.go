@@ -0,0 +1,127 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// StereoFrame 双目帧（左右目图像成对出现）
+type StereoFrame struct {
+	Left   image.Image  // 左目图像（*image.Gray或*image.Gray16）
+	Right  image.Image  // 右目图像（*image.Gray或*image.Gray16）
+	Config DeviceConfig // 原始帧信息
+}
+
+// DecodeStereo 将左右交织的双目帧拆分为左右目图像
+//
+//	@param	data	帧数据
+//	@param	info	帧信息（Y8I或Y12I）
+//	@return	双目帧
+//	@return	异常信息
+func DecodeStereo(data []byte, info *DeviceConfig) (*StereoFrame, error) {
+	if info == nil {
+		return nil, errors.Join(ErrFrameSizeMismatch, errors.New("empty frame size"))
+	}
+
+	// 按格式拆分
+	res := &StereoFrame{Config: *info}
+	switch info.Format {
+	case FOURCC_Y8I:
+		left, right, err := SplitY8I(data, info)
+		if err != nil {
+			return nil, err
+		}
+		res.Left, res.Right = left, right
+	case FOURCC_Y12I:
+		left, right, err := SplitY12I(data, info)
+		if err != nil {
+			return nil, err
+		}
+		res.Left, res.Right = left, right
+	default:
+		return nil, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q is not stereo", info.Format))
+	}
+
+	// OK
+	return res, nil
+}
+
+// SplitY8I 拆分Y8I帧（每个像素依次为左目8位、右目8位）
+//
+//	@param	data	帧数据
+//	@param	info	帧信息
+//	@return	左目图像
+//	@return	右目图像
+//	@return	异常信息
+func SplitY8I(data []byte, info *DeviceConfig) (*image.Gray, *image.Gray, error) {
+	// 计算行跨度
+	if info == nil {
+		return nil, nil, errors.Join(ErrFrameSizeMismatch, errors.New("empty frame size"))
+	}
+	if info.Format != FOURCC_Y8I {
+		return nil, nil, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q is not Y8I", info.Format))
+	}
+	stride, err := frameStride(data, info, int(info.Width)*2)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 逐行拆分
+	rect := image.Rect(0, 0, int(info.Width), int(info.Height))
+	left, right := image.NewGray(rect), image.NewGray(rect)
+	for y := 0; y < rect.Dy(); y++ {
+		src := data[y*stride:]
+		l := left.Pix[y*left.Stride:]
+		r := right.Pix[y*right.Stride:]
+		for x := 0; x < rect.Dx(); x++ {
+			l[x] = src[2*x]
+			r[x] = src[2*x+1]
+		}
+	}
+
+	// OK
+	return left, right, nil
+}
+
+// SplitY12I 拆分Y12I帧（每个像素3字节，依次存放左目12位和右目12位）
+//
+// 有效位会被扩展到完整的16位范围
+//
+//	@param	data	帧数据
+//	@param	info	帧信息
+//	@return	左目图像
+//	@return	右目图像
+//	@return	异常信息
+func SplitY12I(data []byte, info *DeviceConfig) (*image.Gray16, *image.Gray16, error) {
+	// 计算行跨度
+	if info == nil {
+		return nil, nil, errors.Join(ErrFrameSizeMismatch, errors.New("empty frame size"))
+	}
+	if info.Format != FOURCC_Y12I {
+		return nil, nil, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q is not Y12I", info.Format))
+	}
+	stride, err := frameStride(data, info, int(info.Width)*3)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 逐行拆分
+	rect := image.Rect(0, 0, int(info.Width), int(info.Height))
+	left, right := image.NewGray16(rect), image.NewGray16(rect)
+	for y := 0; y < rect.Dy(); y++ {
+		src := data[y*stride:]
+		l := left.Pix[y*left.Stride:]
+		r := right.Pix[y*right.Stride:]
+		for x := 0; x < rect.Dx(); x++ {
+			px := src[3*x : 3*x+3]
+			lv := expandGray16(uint16(px[0])|uint16(px[1]&0x0f)<<8, 12)
+			rv := expandGray16(uint16(px[1]>>4)|uint16(px[2])<<4, 12)
+			l[2*x], l[2*x+1] = byte(lv>>8), byte(lv)
+			r[2*x], r[2*x+1] = byte(rv>>8), byte(rv)
+		}
+	}
+
+	// OK
+	return left, right, nil
+}
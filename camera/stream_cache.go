@@ -0,0 +1,193 @@
+package camera
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultStreamCacheFrames 默认GOP缓存的最大帧数（超过后清空GOP，等待下一个关键帧）
+const DefaultStreamCacheFrames = 300
+
+// VP8关键帧起始码
+var vp8KeyframeStartCode = []byte{0x9d, 0x01, 0x2a}
+
+// IsStreamCacheFormat 格式是否支持码流缓存（H264、HEVC、VP8）
+//
+//	@param	format	帧格式
+//	@return	是否支持
+func IsStreamCacheFormat(format Fourcc) bool {
+	switch format {
+	case FOURCC_H264, FOURCC_HEVC, FOURCC_VP8:
+		return true
+	default:
+		return false
+	}
+}
+
+// StreamCache 压缩码流缓存，保存最新的参数集（SPS/PPS/VPS）与最近一个GOP，
+// 使中途接入的消费者能够先拿到参数集与关键帧，而不是无法解码的P帧
+type StreamCache struct {
+	mutex     sync.Mutex   // 互斥锁
+	maxFrames int          // GOP最大缓存帧数
+	config    DeviceConfig // 当前码流配置
+	paramSets [3][]byte    // 最新的参数集（按VPS、SPS、PPS顺序，H264与VP8不使用VPS）
+	gop       []*Frame     // 从最近关键帧开始的帧列表
+}
+
+// NewStreamCache 创建码流缓存
+//
+//	@param	maxFrames	GOP最大缓存帧数（小于等于0时使用DefaultStreamCacheFrames）
+//	@return	码流缓存
+func NewStreamCache(maxFrames int) *StreamCache {
+	if maxFrames <= 0 {
+		maxFrames = DefaultStreamCacheFrames
+	}
+	return &StreamCache{maxFrames: maxFrames}
+}
+
+// 解析帧中的参数集并判断是否为关键帧
+//
+//	@param	frame	帧
+//	@return	是否为关键帧
+func (p *StreamCache) scan(frame *Frame) bool {
+	keyframe := false
+	switch frame.Config.Format {
+	case FOURCC_H264:
+		for _, nal := range ParseH264NALUnits(frame.Data) {
+			switch nal.Type {
+			case H264NALIDR:
+				keyframe = true
+			case H264NALSPS:
+				p.paramSets[1] = append([]byte(nil), nal.Data...)
+			case H264NALPPS:
+				p.paramSets[2] = append([]byte(nil), nal.Data...)
+			}
+		}
+	case FOURCC_HEVC:
+		for _, nal := range ParseHEVCNALUnits(frame.Data) {
+			switch {
+			case nal.Type.IsIRAP():
+				keyframe = true
+			case nal.Type >= HEVCNALVPS && nal.Type <= HEVCNALPPS:
+				p.paramSets[nal.Type-HEVCNALVPS] = append([]byte(nil), nal.Data...)
+			}
+		}
+	case FOURCC_VP8:
+		// 帧标记最低位为0且携带起始码的是关键帧
+		keyframe = len(frame.Data) >= 10 && frame.Data[0]&0x01 == 0 && bytes.Equal(frame.Data[3:6], vp8KeyframeStartCode)
+	}
+	return keyframe
+}
+
+// Push 写入一帧（帧数据会被拷贝；码流配置变化时会清空已缓存的内容）
+//
+//	@param	frame	帧
+//	@return	是否为关键帧
+//	@return	异常信息
+func (p *StreamCache) Push(frame *Frame) (bool, error) {
+	if frame == nil || len(frame.Data) == 0 {
+		return false, errors.Join(ErrInvalidParam, errors.New("empty frame"))
+	}
+	if !IsStreamCacheFormat(frame.Config.Format) {
+		return false, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("stream cache does not support %s", frame.Config.Format))
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// 码流配置变化
+	if !p.config.Eq(&frame.Config) {
+		p.reset()
+		p.config = frame.Config
+	}
+
+	// 从关键帧开始新的GOP，GOP开始前的非关键帧直接丢弃
+	keyframe := p.scan(frame)
+	switch {
+	case keyframe:
+		p.gop = append(p.gop[:0], frame.Clone())
+	case len(p.gop) >= p.maxFrames:
+		p.gop = nil
+	case len(p.gop) > 0:
+		p.gop = append(p.gop, frame.Clone())
+	}
+	return keyframe, nil
+}
+
+// ParameterSets 以Annex-B格式获取最新的参数集（VP8或尚未收到参数集时返回nil）
+func (p *StreamCache) ParameterSets() []byte {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.parameterSets()
+}
+
+// 以Annex-B格式拼接参数集（无锁）
+func (p *StreamCache) parameterSets() []byte {
+	var nals [][]byte
+	for _, nal := range p.paramSets {
+		if len(nal) > 0 {
+			nals = append(nals, nal)
+		}
+	}
+	if len(nals) == 0 {
+		return nil
+	}
+	return joinAnnexB(nals)
+}
+
+// GOP 获取从最近关键帧开始的缓存帧（首帧为关键帧，且在关键帧缺少参数集时补全参数集）
+//
+//	@return	帧列表（尚未收到关键帧时为nil）
+func (p *StreamCache) GOP() []*Frame {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if len(p.gop) == 0 {
+		return nil
+	}
+	res := make([]*Frame, len(p.gop))
+	for i, frame := range p.gop {
+		res[i] = frame.Clone()
+	}
+
+	// 关键帧未携带SPS时在其前面补充参数集
+	if params := p.parameterSets(); params != nil && !p.hasSPS(res[0]) {
+		res[0].Data = append(params, res[0].Data...)
+	}
+	return res
+}
+
+// 帧内是否携带序列参数集
+func (p *StreamCache) hasSPS(frame *Frame) bool {
+	switch frame.Config.Format {
+	case FOURCC_H264:
+		for _, nal := range ParseH264NALUnits(frame.Data) {
+			if nal.Type == H264NALSPS {
+				return true
+			}
+		}
+	case FOURCC_HEVC:
+		for _, nal := range ParseHEVCNALUnits(frame.Data) {
+			if nal.Type == HEVCNALSPS {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Reset 清空缓存
+func (p *StreamCache) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.reset()
+}
+
+// 清空缓存（无锁）
+func (p *StreamCache) reset() {
+	p.config = DeviceConfig{}
+	p.paramSets = [3][]byte{}
+	p.gop = nil
+}
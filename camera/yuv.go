@@ -0,0 +1,124 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// 打包YUV 4:2:2格式中Y0、U、Y1、V的字节偏移
+var yuv422Layouts = map[Fourcc][4]int{
+	FOURCC_YUYV: {0, 1, 2, 3},
+	FOURCC_YUY2: {0, 1, 2, 3},
+	FOURCC_YVYU: {0, 3, 2, 1},
+	FOURCC_YVY2: {0, 3, 2, 1},
+	FOURCC_UYVY: {1, 0, 3, 2},
+	FOURCC_VYUY: {1, 2, 3, 0},
+	FOURCC_YYUV: {0, 2, 1, 3},
+}
+
+// DecodeYUV422 将打包YUV 4:2:2格式的帧转换为RGBA图像（BT.601有限范围）
+//
+// YUYV会直接使用平台加速内核，其他排列方式逐行重排为YUYV后再转换
+//
+//	@param	data	帧数据
+//	@param	info	帧信息
+//	@return	RGBA图像
+//	@return	异常信息
+func DecodeYUV422(data []byte, info *DeviceConfig) (*image.RGBA, error) {
+	// 获取排列方式
+	if info == nil {
+		return nil, errors.Join(ErrFrameSizeMismatch, errors.New("empty frame size"))
+	}
+	layout, ok := yuv422Layouts[info.Format]
+	if !ok {
+		return nil, errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q is not packed yuv 4:2:2", info.Format))
+	}
+	width, height := int(info.Width), int(info.Height)
+	rowBytes := (width + 1) / 2 * 4
+	stride, err := frameStride(data, info, rowBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// 非YUYV排列需要重排缓冲区
+	var tmp []byte
+	if layout != [4]int{0, 1, 2, 3} {
+		tmp = make([]byte, rowBytes)
+	}
+
+	// 逐行转换
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		src := data[y*stride : y*stride+rowBytes]
+		if tmp != nil {
+			for i := 0; i < rowBytes; i += 4 {
+				tmp[i], tmp[i+1], tmp[i+2], tmp[i+3] = src[i+layout[0]], src[i+layout[1]], src[i+layout[2]], src[i+layout[3]]
+			}
+			src = tmp
+		}
+		yuyvToRGBARow(img.Pix[y*img.Stride:y*img.Stride+width*4], src)
+	}
+
+	// OK
+	return img, nil
+}
+
+// 计算4:2:0帧各平面大小
+func yuv420PlaneSize(info *DeviceConfig) (int, int) {
+	w, h := int(info.Width), int(info.Height)
+	return w * h, ((w + 1) / 2) * ((h + 1) / 2)
+}
+
+// 检查4:2:0帧的格式和长度
+func checkYUV420(data []byte, info *DeviceConfig, format Fourcc) error {
+	if info == nil || info.Width == 0 || info.Height == 0 {
+		return errors.Join(ErrFrameSizeMismatch, errors.New("empty frame size"))
+	}
+	if info.Format != format {
+		return errors.Join(ErrUnsupportedFrameFormat, fmt.Errorf("format %q is not %s", info.Format, format))
+	}
+	ySize, cSize := yuv420PlaneSize(info)
+	if len(data) < ySize+2*cSize {
+		return errors.Join(ErrFrameSizeMismatch, fmt.Errorf("need %d bytes, got %d", ySize+2*cSize, len(data)))
+	}
+	return nil
+}
+
+// ConvertNV12ToI420 将NV12帧转换为I420（YU12）帧
+//
+//	@param	data	帧数据
+//	@param	info	帧信息
+//	@return	I420帧数据
+//	@return	异常信息
+func ConvertNV12ToI420(data []byte, info *DeviceConfig) ([]byte, error) {
+	if err := checkYUV420(data, info, FOURCC_NV12); err != nil {
+		return nil, err
+	}
+
+	// 拷贝亮度平面并拆分色度平面
+	ySize, cSize := yuv420PlaneSize(info)
+	res := make([]byte, ySize+2*cSize)
+	copy(res, data[:ySize])
+	deinterleaveUV(res[ySize:ySize+cSize], res[ySize+cSize:], data[ySize:ySize+2*cSize])
+	return res, nil
+}
+
+// ConvertI420ToNV12 将I420（YU12）帧转换为NV12帧
+//
+//	@param	data	帧数据
+//	@param	info	帧信息
+//	@return	NV12帧数据
+//	@return	异常信息
+func ConvertI420ToNV12(data []byte, info *DeviceConfig) ([]byte, error) {
+	if err := checkYUV420(data, info, FOURCC_YUV420); err != nil {
+		return nil, err
+	}
+
+	// 拷贝亮度平面并合并色度平面
+	ySize, cSize := yuv420PlaneSize(info)
+	res := make([]byte, ySize+2*cSize)
+	copy(res, data[:ySize])
+	interleaveUV(res[ySize:], data[ySize:ySize+cSize], data[ySize+cSize:ySize+2*cSize])
+	return res, nil
+}
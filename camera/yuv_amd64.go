@@ -0,0 +1,80 @@
+//go:build !purego
+
+package camera
+
+// 查询CPU特性
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// 查询操作系统已启用的扩展寄存器状态
+func xgetbv() (eax, edx uint32)
+
+// YUYV转RGBA（AVX2实现，pixels必须为8的倍数）
+//
+//go:noescape
+func yuyvToRGBAAVX2(dst, src *byte, pixels int)
+
+// UV交织拆分（AVX2实现，n必须为16的倍数）
+//
+//go:noescape
+func deinterleaveUVAVX2(u, v, uv *byte, n int)
+
+// UV交织合并（AVX2实现，n必须为16的倍数）
+//
+//go:noescape
+func interleaveUVAVX2(uv, u, v *byte, n int)
+
+// 检查CPU和操作系统是否支持AVX2
+func hasAVX2() bool {
+	// 需要操作系统启用XSAVE并支持AVX
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 7 {
+		return false
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	if ecx1&(1<<27) == 0 || ecx1&(1<<28) == 0 {
+		return false
+	}
+	// 操作系统需要保存XMM和YMM寄存器
+	if xcr0, _ := xgetbv(); xcr0&0x6 != 0x6 {
+		return false
+	}
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&(1<<5) != 0
+}
+
+// 支持AVX2时替换行转换内核
+func init() {
+	if !hasAVX2() {
+		return
+	}
+	yuyvToRGBARow = yuyvToRGBARowAVX2
+	deinterleaveUV = deinterleaveUVAVX2Row
+	interleaveUV = interleaveUVAVX2Row
+}
+
+// YUYV行转RGBA行（AVX2加速，剩余像素使用纯Go实现）
+func yuyvToRGBARowAVX2(dst, src []byte) {
+	n := len(dst) / 4 &^ 7
+	if n > 0 {
+		yuyvToRGBAAVX2(&dst[0], &src[0], n)
+	}
+	yuyvToRGBARowGeneric(dst[4*n:], src[2*n:])
+}
+
+// UV交织行拆分（AVX2加速，剩余部分使用纯Go实现）
+func deinterleaveUVAVX2Row(u, v, uv []byte) {
+	n := len(u) &^ 15
+	if n > 0 {
+		deinterleaveUVAVX2(&u[0], &v[0], &uv[0], n)
+	}
+	deinterleaveUVGeneric(u[n:], v[n:], uv[2*n:])
+}
+
+// UV交织行合并（AVX2加速，剩余部分使用纯Go实现）
+func interleaveUVAVX2Row(uv, u, v []byte) {
+	n := len(u) &^ 15
+	if n > 0 {
+		interleaveUVAVX2(&uv[0], &u[0], &v[0], n)
+	}
+	interleaveUVGeneric(uv[2*n:], u[n:], v[n:])
+}
@@ -0,0 +1,207 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// YUYV字转有符号字时减去的偏移（Y-16、U-128、Y-16、V-128）
+DATA yuyvBias<>+0(SB)/8, $0x0080001000800010
+DATA yuyvBias<>+8(SB)/8, $0x0080001000800010
+DATA yuyvBias<>+16(SB)/8, $0x0080001000800010
+DATA yuyvBias<>+24(SB)/8, $0x0080001000800010
+GLOBL yuyvBias<>(SB), RODATA|NOPTR, $32
+
+// 每个像素取出(C, 0)字对
+DATA yuyvShufC<>+0(SB)/8, $0x8080050480800100
+DATA yuyvShufC<>+8(SB)/8, $0x80800d0c80800908
+DATA yuyvShufC<>+16(SB)/8, $0x8080050480800100
+DATA yuyvShufC<>+24(SB)/8, $0x80800d0c80800908
+GLOBL yuyvShufC<>(SB), RODATA|NOPTR, $32
+
+// 每个像素取出(D, E)字对
+DATA yuyvShufDE<>+0(SB)/8, $0x0706030207060302
+DATA yuyvShufDE<>+8(SB)/8, $0x0f0e0b0a0f0e0b0a
+DATA yuyvShufDE<>+16(SB)/8, $0x0706030207060302
+DATA yuyvShufDE<>+24(SB)/8, $0x0f0e0b0a0f0e0b0a
+GLOBL yuyvShufDE<>(SB), RODATA|NOPTR, $32
+
+// 亮度系数(298, 0)
+DATA yuyvCoefY<>+0(SB)/8, $0x0000012a0000012a
+DATA yuyvCoefY<>+8(SB)/8, $0x0000012a0000012a
+DATA yuyvCoefY<>+16(SB)/8, $0x0000012a0000012a
+DATA yuyvCoefY<>+24(SB)/8, $0x0000012a0000012a
+GLOBL yuyvCoefY<>(SB), RODATA|NOPTR, $32
+
+// 红色色度系数(0, 409)
+DATA yuyvCoefR<>+0(SB)/8, $0x0199000001990000
+DATA yuyvCoefR<>+8(SB)/8, $0x0199000001990000
+DATA yuyvCoefR<>+16(SB)/8, $0x0199000001990000
+DATA yuyvCoefR<>+24(SB)/8, $0x0199000001990000
+GLOBL yuyvCoefR<>(SB), RODATA|NOPTR, $32
+
+// 绿色色度系数(-100, -208)
+DATA yuyvCoefG<>+0(SB)/8, $0xff30ff9cff30ff9c
+DATA yuyvCoefG<>+8(SB)/8, $0xff30ff9cff30ff9c
+DATA yuyvCoefG<>+16(SB)/8, $0xff30ff9cff30ff9c
+DATA yuyvCoefG<>+24(SB)/8, $0xff30ff9cff30ff9c
+GLOBL yuyvCoefG<>(SB), RODATA|NOPTR, $32
+
+// 蓝色色度系数(516, 0)
+DATA yuyvCoefB<>+0(SB)/8, $0x0000020400000204
+DATA yuyvCoefB<>+8(SB)/8, $0x0000020400000204
+DATA yuyvCoefB<>+16(SB)/8, $0x0000020400000204
+DATA yuyvCoefB<>+24(SB)/8, $0x0000020400000204
+GLOBL yuyvCoefB<>(SB), RODATA|NOPTR, $32
+
+// 舍入偏移128
+DATA yuyvRound<>+0(SB)/8, $0x0000008000000080
+DATA yuyvRound<>+8(SB)/8, $0x0000008000000080
+DATA yuyvRound<>+16(SB)/8, $0x0000008000000080
+DATA yuyvRound<>+24(SB)/8, $0x0000008000000080
+GLOBL yuyvRound<>(SB), RODATA|NOPTR, $32
+
+// 不透明度255
+DATA yuyvAlpha<>+0(SB)/8, $0x000000ff000000ff
+DATA yuyvAlpha<>+8(SB)/8, $0x000000ff000000ff
+DATA yuyvAlpha<>+16(SB)/8, $0x000000ff000000ff
+DATA yuyvAlpha<>+24(SB)/8, $0x000000ff000000ff
+GLOBL yuyvAlpha<>(SB), RODATA|NOPTR, $32
+
+// RRRRGGGGBBBBAAAA重排为RGBARGBARGBARGBA
+DATA yuyvShufRGBA<>+0(SB)/8, $0x0d0905010c080400
+DATA yuyvShufRGBA<>+8(SB)/8, $0x0f0b07030e0a0602
+DATA yuyvShufRGBA<>+16(SB)/8, $0x0d0905010c080400
+DATA yuyvShufRGBA<>+24(SB)/8, $0x0f0b07030e0a0602
+GLOBL yuyvShufRGBA<>(SB), RODATA|NOPTR, $32
+
+// UVUV...重排为UUUUUUUUVVVVVVVV
+DATA uvShufSplit<>+0(SB)/8, $0x0e0c0a0806040200
+DATA uvShufSplit<>+8(SB)/8, $0x0f0d0b0907050301
+DATA uvShufSplit<>+16(SB)/8, $0x0e0c0a0806040200
+DATA uvShufSplit<>+24(SB)/8, $0x0f0d0b0907050301
+GLOBL uvShufSplit<>(SB), RODATA|NOPTR, $32
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
+
+// func yuyvToRGBAAVX2(dst, src *byte, pixels int)
+//
+// 每次处理8个像素，pixels必须为8的倍数且大于0
+TEXT ·yuyvToRGBAAVX2(SB), NOSPLIT, $0-24
+	MOVQ dst+0(FP), DI
+	MOVQ src+8(FP), SI
+	MOVQ pixels+16(FP), CX
+
+	VMOVDQU yuyvBias<>(SB), Y15
+	VMOVDQU yuyvShufC<>(SB), Y14
+	VMOVDQU yuyvShufDE<>(SB), Y13
+	VMOVDQU yuyvCoefY<>(SB), Y12
+	VMOVDQU yuyvCoefR<>(SB), Y11
+	VMOVDQU yuyvCoefG<>(SB), Y10
+	VMOVDQU yuyvCoefB<>(SB), Y9
+	VMOVDQU yuyvRound<>(SB), Y8
+	VMOVDQU yuyvAlpha<>(SB), Y7
+	VMOVDQU yuyvShufRGBA<>(SB), Y6
+
+yuyvLoop:
+	// 16字节YUYV零扩展为16个字，每个128位通道4个像素
+	VPMOVZXBW (SI), Y0
+	VPSUBW    Y15, Y0, Y0
+
+	// 298*C + 128
+	VPSHUFB  Y14, Y0, Y1
+	VPMADDWD Y12, Y1, Y1
+	VPADDD   Y8, Y1, Y1
+
+	// 叠加色度分量
+	VPSHUFB  Y13, Y0, Y2
+	VPMADDWD Y11, Y2, Y3
+	VPADDD   Y1, Y3, Y3
+	VPMADDWD Y10, Y2, Y4
+	VPADDD   Y1, Y4, Y4
+	VPMADDWD Y9, Y2, Y5
+	VPADDD   Y1, Y5, Y5
+	VPSRAD   $8, Y3, Y3
+	VPSRAD   $8, Y4, Y4
+	VPSRAD   $8, Y5, Y5
+
+	// 饱和收窄到字节并交织为RGBA
+	VPACKSSDW Y4, Y3, Y3
+	VPACKSSDW Y7, Y5, Y5
+	VPACKUSWB Y5, Y3, Y3
+	VPSHUFB   Y6, Y3, Y3
+	VMOVDQU   Y3, (DI)
+
+	ADDQ $16, SI
+	ADDQ $32, DI
+	SUBQ $8, CX
+	JNZ  yuyvLoop
+
+	VZEROUPPER
+	RET
+
+// func deinterleaveUVAVX2(u, v, uv *byte, n int)
+//
+// 每次处理16组UV，n必须为16的倍数且大于0
+TEXT ·deinterleaveUVAVX2(SB), NOSPLIT, $0-32
+	MOVQ u+0(FP), DI
+	MOVQ v+8(FP), DX
+	MOVQ uv+16(FP), SI
+	MOVQ n+24(FP), CX
+
+	VMOVDQU uvShufSplit<>(SB), Y15
+
+splitLoop:
+	VMOVDQU      (SI), Y0
+	VPSHUFB      Y15, Y0, Y0
+	VPERMQ       $0xd8, Y0, Y0
+	VMOVDQU      X0, (DI)
+	VEXTRACTI128 $1, Y0, (DX)
+
+	ADDQ $32, SI
+	ADDQ $16, DI
+	ADDQ $16, DX
+	SUBQ $16, CX
+	JNZ  splitLoop
+
+	VZEROUPPER
+	RET
+
+// func interleaveUVAVX2(uv, u, v *byte, n int)
+//
+// 每次处理16组UV，n必须为16的倍数且大于0
+TEXT ·interleaveUVAVX2(SB), NOSPLIT, $0-32
+	MOVQ uv+0(FP), DI
+	MOVQ u+8(FP), SI
+	MOVQ v+16(FP), DX
+	MOVQ n+24(FP), CX
+
+mergeLoop:
+	VMOVDQU    (SI), X0
+	VMOVDQU    (DX), X1
+	VPUNPCKLBW X1, X0, X2
+	VPUNPCKHBW X1, X0, X3
+	VMOVDQU    X2, (DI)
+	VMOVDQU    X3, 16(DI)
+
+	ADDQ $16, SI
+	ADDQ $16, DX
+	ADDQ $32, DI
+	SUBQ $16, CX
+	JNZ  mergeLoop
+
+	RET
@@ -0,0 +1,52 @@
+//go:build !purego
+
+package camera
+
+// YUYV转RGBA（NEON实现，pixels必须为16的倍数）
+//
+//go:noescape
+func yuyvToRGBANEON(dst, src *byte, pixels int)
+
+// UV交织拆分（NEON实现，n必须为16的倍数）
+//
+//go:noescape
+func deinterleaveUVNEON(u, v, uv *byte, n int)
+
+// UV交织合并（NEON实现，n必须为16的倍数）
+//
+//go:noescape
+func interleaveUVNEON(uv, u, v *byte, n int)
+
+// ARMv8的高级SIMD为必选特性，直接替换行转换内核
+func init() {
+	yuyvToRGBARow = yuyvToRGBARowNEON
+	deinterleaveUV = deinterleaveUVNEONRow
+	interleaveUV = interleaveUVNEONRow
+}
+
+// YUYV行转RGBA行（NEON加速，剩余像素使用纯Go实现）
+func yuyvToRGBARowNEON(dst, src []byte) {
+	n := len(dst) / 4 &^ 15
+	if n > 0 {
+		yuyvToRGBANEON(&dst[0], &src[0], n)
+	}
+	yuyvToRGBARowGeneric(dst[4*n:], src[2*n:])
+}
+
+// UV交织行拆分（NEON加速，剩余部分使用纯Go实现）
+func deinterleaveUVNEONRow(u, v, uv []byte) {
+	n := len(u) &^ 15
+	if n > 0 {
+		deinterleaveUVNEON(&u[0], &v[0], &uv[0], n)
+	}
+	deinterleaveUVGeneric(u[n:], v[n:], uv[2*n:])
+}
+
+// UV交织行合并（NEON加速，剩余部分使用纯Go实现）
+func interleaveUVNEONRow(uv, u, v []byte) {
+	n := len(u) &^ 15
+	if n > 0 {
+		interleaveUVNEON(&uv[0], &u[0], &v[0], n)
+	}
+	interleaveUVGeneric(uv[2*n:], u[n:], v[n:])
+}
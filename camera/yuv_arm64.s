@@ -0,0 +1,145 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// 部分NEON指令在较早版本的Go汇编器中没有助记符，以WORD编码并在注释中给出对应指令
+
+// func yuyvToRGBANEON(dst, src *byte, pixels int)
+//
+// 每次处理16个像素，pixels必须为16的倍数且大于0
+TEXT ·yuyvToRGBANEON(SB), NOSPLIT, $0-24
+	MOVD dst+0(FP), R0
+	MOVD src+8(FP), R1
+	MOVD pixels+16(FP), R2
+
+	// 常量
+	MOVD  $16, R3
+	VDUP  R3, V20.H8
+	MOVD  $128, R3
+	VDUP  R3, V21.H8
+	VDUP  R3, V27.S4
+	MOVD  $298, R3
+	VDUP  R3, V22.H8
+	MOVD  $409, R3
+	VDUP  R3, V23.H8
+	MOVD  $-100, R3
+	VDUP  R3, V24.H8
+	MOVD  $-208, R3
+	VDUP  R3, V25.H8
+	MOVD  $516, R3
+	VDUP  R3, V26.H8
+	VMOVI $255, V31.B16
+
+yuyvLoop:
+	// 32字节YUYV拆分为偶数像素Y、U、奇数像素Y、V
+	VLD4.P 32(R1), [V0.B8, V1.B8, V2.B8, V3.B8]
+	VUXTL  V0.B8, V0.H8
+	VUXTL  V1.B8, V1.H8
+	VUXTL  V2.B8, V2.H8
+	VUXTL  V3.B8, V3.H8
+	VSUB   V20.H8, V0.H8, V0.H8
+	VSUB   V21.H8, V1.H8, V1.H8
+	VSUB   V20.H8, V2.H8, V2.H8
+	VSUB   V21.H8, V3.H8, V3.H8
+
+	// 色度分量（含舍入偏移）
+	VMOV    V27.B16, V4.B16
+	VMOV    V27.B16, V5.B16
+	WORD    $0x0e778064 // VSMLAL V23.H4, V3.H4, V4.S4
+	WORD    $0x4e778065 // VSMLAL2 V23.H8, V3.H8, V5.S4
+	VMOV    V27.B16, V6.B16
+	VMOV    V27.B16, V7.B16
+	WORD    $0x0e788026 // VSMLAL V24.H4, V1.H4, V6.S4
+	WORD    $0x4e788027 // VSMLAL2 V24.H8, V1.H8, V7.S4
+	WORD    $0x0e798066 // VSMLAL V25.H4, V3.H4, V6.S4
+	WORD    $0x4e798067 // VSMLAL2 V25.H8, V3.H8, V7.S4
+	VMOV    V27.B16, V16.B16
+	VMOV    V27.B16, V17.B16
+	WORD    $0x0e7a8030 // VSMLAL V26.H4, V1.H4, V16.S4
+	WORD    $0x4e7a8031 // VSMLAL2 V26.H8, V1.H8, V17.S4
+
+	// 亮度分量
+	WORD    $0x0e76c008 // VSMULL V22.H4, V0.H4, V8.S4
+	WORD    $0x4e76c009 // VSMULL2 V22.H8, V0.H8, V9.S4
+	WORD    $0x0e76c04a // VSMULL V22.H4, V2.H4, V10.S4
+	WORD    $0x4e76c04b // VSMULL2 V22.H8, V2.H8, V11.S4
+
+	// R
+	VADD    V4.S4, V8.S4, V12.S4
+	VADD    V5.S4, V9.S4, V13.S4
+	WORD    $0x0f188592 // VSHRN $8, V12.S4, V18.H4
+	WORD    $0x4f1885b2 // VSHRN2 $8, V13.S4, V18.H8
+	WORD    $0x2e212a52 // VSQXTUN V18.H8, V18.B8
+	VADD    V4.S4, V10.S4, V12.S4
+	VADD    V5.S4, V11.S4, V13.S4
+	WORD    $0x0f188593 // VSHRN $8, V12.S4, V19.H4
+	WORD    $0x4f1885b3 // VSHRN2 $8, V13.S4, V19.H8
+	WORD    $0x2e212a73 // VSQXTUN V19.H8, V19.B8
+	VZIP1   V19.B16, V18.B16, V28.B16
+
+	// G
+	VADD    V6.S4, V8.S4, V12.S4
+	VADD    V7.S4, V9.S4, V13.S4
+	WORD    $0x0f188592 // VSHRN $8, V12.S4, V18.H4
+	WORD    $0x4f1885b2 // VSHRN2 $8, V13.S4, V18.H8
+	WORD    $0x2e212a52 // VSQXTUN V18.H8, V18.B8
+	VADD    V6.S4, V10.S4, V12.S4
+	VADD    V7.S4, V11.S4, V13.S4
+	WORD    $0x0f188593 // VSHRN $8, V12.S4, V19.H4
+	WORD    $0x4f1885b3 // VSHRN2 $8, V13.S4, V19.H8
+	WORD    $0x2e212a73 // VSQXTUN V19.H8, V19.B8
+	VZIP1   V19.B16, V18.B16, V29.B16
+
+	// B
+	VADD    V16.S4, V8.S4, V12.S4
+	VADD    V17.S4, V9.S4, V13.S4
+	WORD    $0x0f188592 // VSHRN $8, V12.S4, V18.H4
+	WORD    $0x4f1885b2 // VSHRN2 $8, V13.S4, V18.H8
+	WORD    $0x2e212a52 // VSQXTUN V18.H8, V18.B8
+	VADD    V16.S4, V10.S4, V12.S4
+	VADD    V17.S4, V11.S4, V13.S4
+	WORD    $0x0f188593 // VSHRN $8, V12.S4, V19.H4
+	WORD    $0x4f1885b3 // VSHRN2 $8, V13.S4, V19.H8
+	WORD    $0x2e212a73 // VSQXTUN V19.H8, V19.B8
+	VZIP1   V19.B16, V18.B16, V30.B16
+
+	// 交织写出16个RGBA像素
+	VST4.P [V28.B16, V29.B16, V30.B16, V31.B16], 64(R0)
+
+	SUBS $16, R2, R2
+	BNE  yuyvLoop
+	RET
+
+// func deinterleaveUVNEON(u, v, uv *byte, n int)
+//
+// 每次处理16组UV，n必须为16的倍数且大于0
+TEXT ·deinterleaveUVNEON(SB), NOSPLIT, $0-32
+	MOVD u+0(FP), R0
+	MOVD v+8(FP), R1
+	MOVD uv+16(FP), R2
+	MOVD n+24(FP), R3
+
+splitLoop:
+	VLD2.P 32(R2), [V0.B16, V1.B16]
+	VST1.P [V0.B16], 16(R0)
+	VST1.P [V1.B16], 16(R1)
+	SUBS   $16, R3, R3
+	BNE    splitLoop
+	RET
+
+// func interleaveUVNEON(uv, u, v *byte, n int)
+//
+// 每次处理16组UV，n必须为16的倍数且大于0
+TEXT ·interleaveUVNEON(SB), NOSPLIT, $0-32
+	MOVD uv+0(FP), R0
+	MOVD u+8(FP), R1
+	MOVD v+16(FP), R2
+	MOVD n+24(FP), R3
+
+mergeLoop:
+	VLD1.P 16(R1), [V0.B16]
+	VLD1.P 16(R2), [V1.B16]
+	VST2.P [V0.B16, V1.B16], 32(R0)
+	SUBS   $16, R3, R3
+	BNE    mergeLoop
+	RET
@@ -0,0 +1,69 @@
+package camera
+
+// 行转换内核（平台加速实现会在初始化时替换）
+var (
+	yuyvToRGBARow  = yuyvToRGBARowGeneric  // YUYV行转RGBA行
+	deinterleaveUV = deinterleaveUVGeneric // UV交织行拆分为U行和V行
+	interleaveUV   = interleaveUVGeneric   // U行和V行合并为UV交织行
+)
+
+// 将整数截断到0~255
+func clampUint8(v int32) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 0xff {
+		return 0xff
+	}
+	return uint8(v)
+}
+
+// 将一个YUV像素转换为RGB（BT.601有限范围，8位定点运算）
+//
+// 加速实现必须与该函数保持逐位一致
+func yuvToRGB(y, u, v uint8) (uint8, uint8, uint8) {
+	c := 298 * (int32(y) - 16)
+	d := int32(u) - 128
+	e := int32(v) - 128
+	return clampUint8((c + 409*e + 128) >> 8),
+		clampUint8((c - 100*d - 208*e + 128) >> 8),
+		clampUint8((c + 516*d + 128) >> 8)
+}
+
+// YUYV行转RGBA行（纯Go实现）
+//
+//	@param	dst	RGBA行（每像素4字节）
+//	@param	src	YUYV行（每像素2字节）
+func yuyvToRGBARowGeneric(dst, src []byte) {
+	pixels := len(dst) / 4
+	for x := 0; x+1 < pixels; x += 2 {
+		px := src[2*x : 2*x+4]
+		out := dst[4*x : 4*x+8]
+		out[0], out[1], out[2] = yuvToRGB(px[0], px[1], px[3])
+		out[4], out[5], out[6] = yuvToRGB(px[2], px[1], px[3])
+		out[3], out[7] = 0xff, 0xff
+	}
+	// 奇数宽度时最后一个像素复用前一组色度
+	if pixels%2 == 1 {
+		x := pixels - 1
+		out := dst[4*x : 4*x+4]
+		out[0], out[1], out[2] = yuvToRGB(src[2*x], src[2*x+1], src[2*x+3])
+		out[3] = 0xff
+	}
+}
+
+// UV交织行拆分为U行和V行（纯Go实现）
+func deinterleaveUVGeneric(u, v, uv []byte) {
+	for i := range u {
+		u[i] = uv[2*i]
+		v[i] = uv[2*i+1]
+	}
+}
+
+// U行和V行合并为UV交织行（纯Go实现）
+func interleaveUVGeneric(uv, u, v []byte) {
+	for i := range u {
+		uv[2*i] = u[i]
+		uv[2*i+1] = v[i]
+	}
+}
@@ -7,8 +7,7 @@ package internal
 */
 import "C"
 import (
-	"crypto/md5"
-	"encoding/hex"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -116,11 +115,19 @@ func convertStatusCode(code C.StatusCode) error {
 
 // Control 相机控制器
 type Control struct {
-	rwmutex           sync.RWMutex        // 读写锁
-	deviceCacheList   camera.DeviceList   // 缓存的相机信息列表
-	handle            C.BecamHandle       // 相机库句柄
-	deviceInfo        camera.Device       // 当前使用的相机信息
-	deviceSupportInfo camera.DeviceConfig // 当前使用的相机支持信息
+	rwmutex           sync.RWMutex            // 读写锁
+	deviceCacheList   camera.DeviceList       // 缓存的相机信息列表
+	handle            C.BecamHandle           // 相机库句柄
+	deviceInfo        camera.Device           // 当前使用的相机信息
+	deviceSupportInfo camera.DeviceConfig     // 当前使用的相机支持信息
+	deviceConfigList  camera.DeviceConfigList // 当前使用的相机配置列表
+	aliases           *camera.DeviceAliases   // 设备别名
+	listNodeTypes     camera.DeviceNodeType   // 列表中包含的节点类型
+
+	corruptFramePolicy camera.CorruptFramePolicy // 损坏帧处理策略
+	streamCache        *camera.StreamCache       // 压缩码流缓存
+	subscribers        camera.ConfigSubscribers  // 配置变更订阅者
+	stats              camera.FrameStats         // 取帧统计
 }
 
 // NewControl 创建一个相机控制器
@@ -129,16 +136,59 @@ func New() *Control {
 	handle := C.BecamNew()
 	// OK
 	return &Control{
-		handle: handle,
+		handle:        handle,
+		streamCache:   camera.NewStreamCache(camera.DefaultStreamCacheFrames),
+		listNodeTypes: camera.NodeCapture,
 	}
 }
 
-// 尝试获取帧
+// 尝试获取帧（按损坏帧处理策略校验帧完整性）
 //
 //	@return 帧数据
 //	@return 帧信息
 //	@return 错误信息
 func (p *Control) tryGetFrame() ([]byte, *camera.DeviceConfig, error) {
+	var corruptErr error
+	for i := 0; i <= camera.CorruptFrameRetryCount; i++ {
+		// 取帧
+		data, err := p.grabFrame()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// 校验帧完整性
+		corruptErr = camera.CheckFrame(data, &p.deviceSupportInfo)
+		if corruptErr == nil {
+			p.stats.Frames++
+			p.stats.Bytes += uint64(len(data))
+			// 缓存压缩码流的参数集与GOP
+			if camera.IsStreamCacheFormat(p.deviceSupportInfo.Format) {
+				_, _ = p.streamCache.Push(&camera.Frame{Data: data, Config: p.deviceSupportInfo})
+			}
+			return data, p.deviceSupportInfo.Clone(), nil
+		}
+		p.stats.CorruptedFrames++
+		switch p.corruptFramePolicy {
+		case camera.CorruptFramePass:
+			return data, p.deviceSupportInfo.Clone(), nil
+		case camera.CorruptFrameRetry:
+			continue
+		case camera.CorruptFrameDrop:
+			return nil, nil, corruptErr
+		default:
+			return data, p.deviceSupportInfo.Clone(), corruptErr
+		}
+	}
+
+	// 重试后仍然损坏
+	return nil, nil, corruptErr
+}
+
+// 从相机库获取一帧
+//
+//	@return 帧数据
+//	@return 错误信息
+func (p *Control) grabFrame() ([]byte, error) {
 	// 声明响应参数
 	var replyData *C.uint8_t
 	var replySize C.size_t
@@ -150,7 +200,7 @@ func (p *Control) tryGetFrame() ([]byte, *camera.DeviceConfig, error) {
 		if err := convertStatusCode(code); err != nil {
 			if i == 100-1 {
 				fmt.Fprintln(os.Stderr, err.Error())
-				return nil, nil, errors.Join(camera.ErrGetFrameFailed, err)
+				return nil, errors.Join(camera.ErrGetFrameFailed, err)
 			}
 		} else {
 			break
@@ -162,7 +212,7 @@ func (p *Control) tryGetFrame() ([]byte, *camera.DeviceConfig, error) {
 	// 拷贝帧
 	data := C.GoBytes(unsafe.Pointer(replyData), C.int(replySize))
 	// 获取帧成功
-	return data, p.deviceSupportInfo.Clone(), nil
+	return data, nil
 }
 
 // --------------------------------------------- 实现Manager接口 --------------------------------------------- //
@@ -193,16 +243,23 @@ func (p *Control) getList() (camera.DeviceList, error) {
 		name := C.GoString(device.name)
 		// 获取设备路径
 		devicePath := C.GoString(device.devicePath)
-		// 计算设备唯一ID
-		idData := md5.Sum([]byte(devicePath + name))
-		id := hex.EncodeToString(idData[:])
-		// 追加到相机列表
-		p.deviceCacheList = append(p.deviceCacheList, &camera.Device{
-			ID:           id,
+		// 根据持久化标识属性计算设备唯一ID
+		dev := &camera.Device{
 			Name:         name,
 			SymbolicLink: devicePath,
-		})
+			Identity:     camera.ResolveDeviceIdentity("/", devicePath),
+			Metadata:     camera.ResolveDeviceMetadata("/", devicePath),
+		}
+		camera.AssignDeviceID(dev)
+		dev.NodeType = camera.DeviceNodeTypeFromCaps(dev.Metadata.Caps)
+		// 跳过未选择的节点类型（如UVC元数据节点）
+		if dev.NodeType&p.listNodeTypes == 0 {
+			continue
+		}
+		// 追加到相机列表
+		p.deviceCacheList = append(p.deviceCacheList, dev)
 	}
+	p.deviceCacheList.DedupeIDs()
 
 	// 返回相机克隆列表
 	return p.deviceCacheList.Clone(), nil
@@ -221,17 +278,27 @@ func (p *Control) GetList() (camera.DeviceList, error) {
 	return p.getList()
 }
 
+// Watch 监听相机接入、移除与变化（每次重新枚举都会更新缓存的相机列表）
+//
+//	@param	ctx	上下文（取消后停止监听并关闭事件通道）
+//	@return	事件通道
+//	@return	异常信息
+func (p *Control) Watch(ctx context.Context) (<-chan camera.DeviceEvent, error) {
+	watcher := &camera.DeviceWatcher{List: p.GetList}
+	return watcher.Watch(ctx)
+}
+
 // GetDeviceWithID 通过相机ID获取缓存的相机信息
 //
-//	@param	id	相机ID
+//	@param	id	相机ID或别名
 //	@return	缓存的相机信息
 //	@return	异常信息
 func (p *Control) GetDeviceWithID(id string) (*camera.Device, error) {
 	// 加读锁
 	p.rwmutex.RLock()
 	defer p.rwmutex.RUnlock()
-	// 执行查找
-	return p.deviceCacheList.Get(id)
+	// 执行查找（兼容别名）
+	return p.aliases.Lookup(p.deviceCacheList, id)
 }
 
 // 通过相机系统路径获取设备的配置信息（无锁）
@@ -260,10 +327,11 @@ func (p *Control) getDeviceConfigInfo(devicePath string) (camera.DeviceConfigLis
 		frameInfo := C.getFrameInfoListItem(reply.videoFrameInfoList, C.size_t(j))
 		// 追加配置信息
 		deviceConfigList = append(deviceConfigList, &camera.DeviceConfig{
-			Width:  uint32(frameInfo.width),
-			Height: uint32(frameInfo.height),
-			FPS:    uint32(frameInfo.fps),
-			Format: camera.NewFourccFromNumber(uint32(frameInfo.format)),
+			Width:    uint32(frameInfo.width),
+			Height:   uint32(frameInfo.height),
+			FPS:      uint32(frameInfo.fps),
+			Interval: camera.FrameIntervalFromFPS(uint32(frameInfo.fps)), // 相机库仅提供整数帧率
+			Format:   camera.NewFourccFromNumber(uint32(frameInfo.format)),
 		})
 	}
 
@@ -276,7 +344,7 @@ func (p *Control) getDeviceConfigInfo(devicePath string) (camera.DeviceConfigLis
 				// 高度是否相等
 				if deviceConfigList[i].Height == deviceConfigList[j].Height {
 					// 按帧率从大到小排序
-					return deviceConfigList[i].FPS >= deviceConfigList[j].FPS
+					return deviceConfigList[i].FrameInterval().Cmp(deviceConfigList[j].FrameInterval()) >= 0
 				}
 				// 按高度从大到小排序
 				return deviceConfigList[i].Height > deviceConfigList[j].Height
@@ -288,13 +356,16 @@ func (p *Control) getDeviceConfigInfo(devicePath string) (camera.DeviceConfigLis
 		return deviceConfigList[i].Format < deviceConfigList[j].Format
 	})
 
+	// 相机库仅提供整数帧率，29.97与30等模式会变成重复配置
+	deviceConfigList = deviceConfigList.Dedupe()
+
 	// 返回配置信息列表
 	return deviceConfigList, nil
 }
 
 // GetDeviceConfigInfo 通过相机ID获取设备的配置信息（有锁）
 //
-//	@param	id	相机ID
+//	@param	id	相机ID或别名
 //	@return	设备配置信息
 //	@return	异常信息
 func (p *Control) GetDeviceConfigInfo(id string) (camera.DeviceConfigList, error) {
@@ -333,7 +404,7 @@ func (p *Control) GetCurrDeviceConfigInfo() (*camera.Device, *camera.DeviceConfi
 
 // Open 打开相机
 //
-//	@param	id		相机ID
+//	@param	id		相机ID或别名
 //	@param	info	分辨率信息
 //	@return	异常信息
 func (p *Control) Open(id string, info camera.DeviceConfig) error {
@@ -354,7 +425,7 @@ func (p *Control) Open(id string, info camera.DeviceConfig) error {
 	}
 
 	// 查询ID对应的相机信息
-	cameraInfo, err := p.deviceCacheList.Get(id)
+	cameraInfo, err := p.aliases.Lookup(p.deviceCacheList, id)
 	if err != nil {
 		return err
 	}
@@ -369,6 +440,31 @@ func (p *Control) Open(id string, info camera.DeviceConfig) error {
 		return err
 	}
 
+	// 打开相机
+	if err := p.open(cameraInfo, yesInfo); err != nil {
+		return err
+	}
+	p.deviceConfigList = configList
+
+	// 尝试获取帧（首帧损坏不影响打开）
+	_, _, err = p.tryGetFrame()
+	if errors.Is(err, camera.ErrFrameCorrupted) {
+		return nil
+	}
+	return err
+}
+
+// 使用指定配置打开相机（无锁）
+//
+//	@param	cameraInfo	相机信息
+//	@param	info		配置信息
+//	@return	异常信息
+func (p *Control) open(cameraInfo *camera.Device, info *camera.DeviceConfig) error {
+	// 检查格式，避免无效的FOURCC以0传给相机库
+	if err := info.Format.Validate(); err != nil {
+		return err
+	}
+
 	// 关闭已打开的相机
 	p.close()
 
@@ -377,10 +473,10 @@ func (p *Control) Open(id string, info camera.DeviceConfig) error {
 	defer C.free(unsafe.Pointer(devicePath))
 	// 转换配置信息
 	var frameInfo C.VideoFrameInfo
-	frameInfo.width = C.uint32_t(yesInfo.Width)
-	frameInfo.height = C.uint32_t(yesInfo.Height)
-	frameInfo.fps = C.uint32_t(yesInfo.FPS)
-	frameInfo.format = C.uint32_t(yesInfo.Format.Number())
+	frameInfo.width = C.uint32_t(info.Width)
+	frameInfo.height = C.uint32_t(info.Height)
+	frameInfo.fps = C.uint32_t(info.FrameInterval().FPS())
+	frameInfo.format = C.uint32_t(info.Format.Number())
 	// 执行打开
 	code := C.BecamOpenDevice(p.handle, devicePath, &frameInfo)
 	if err := convertStatusCode(code); err != nil {
@@ -390,13 +486,210 @@ func (p *Control) Open(id string, info camera.DeviceConfig) error {
 
 	// 赋值当前使用的相机信息
 	p.deviceInfo = *cameraInfo
+	p.deviceSupportInfo = *info
+	p.stats = camera.FrameStats{}
+	return nil
+}
+
+// 等待有效帧（无锁）
+//
+//	@param	timeout	超时时间
+//	@return	异常信息（超时返回ErrFrameTimeout）
+func (p *Control) waitFrame(timeout time.Duration) error {
+	var lastErr error
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		// 取帧
+		data, err := p.grabFrame()
+		if err == nil {
+			if len(data) == 0 {
+				err = camera.ErrFrameCorrupted
+			} else {
+				err = camera.CheckFrame(data, &p.deviceSupportInfo)
+			}
+		}
+		if err == nil {
+			p.stats.Frames++
+			p.stats.Bytes += uint64(len(data))
+			// 缓存压缩码流的参数集与GOP
+			if camera.IsStreamCacheFormat(p.deviceSupportInfo.Format) {
+				_, _ = p.streamCache.Push(&camera.Frame{Data: data, Config: p.deviceSupportInfo})
+			}
+			return nil
+		}
+		lastErr = err
+		// 稍后重试
+		time.Sleep(time.Millisecond * 10)
+	}
+	if lastErr != nil {
+		return errors.Join(camera.ErrFrameTimeout, lastErr)
+	}
+	return camera.ErrFrameTimeout
+}
+
+// OpenBest 按评分顺序依次尝试打开候选配置，并确认能收到有效帧
+//
+//	@param	id			相机ID或别名
+//	@param	selector	配置选择器
+//	@param	timeout		等待有效帧的超时时间（为0时使用DefaultOpenFrameTimeout）
+//	@return	打开结果
+//	@return	异常信息
+func (p *Control) OpenBest(id string, selector *camera.ConfigSelector, timeout time.Duration) (*camera.OpenResult, error) {
+	// 检查参数
+	if selector == nil {
+		return nil, camera.ErrInvalidParam
+	}
+	if timeout <= 0 {
+		timeout = camera.DefaultOpenFrameTimeout
+	}
+
+	// 操作尝试加锁
+	ok := p.rwmutex.TryLock()
+	if !ok {
+		return nil, camera.ErrDeviceRepeatOpening
+	}
+	defer p.rwmutex.Unlock()
+
+	// 相机列表为空时获取相机列表
+	if len(p.deviceCacheList) == 0 {
+		// 获取相机列表（必须使用无锁）
+		_, err := p.getList()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 查询ID对应的相机信息
+	cameraInfo, err := p.aliases.Lookup(p.deviceCacheList, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取配置列表并评分
+	configList, err := p.getDeviceConfigInfo(cameraInfo.SymbolicLink)
+	if err != nil {
+		return nil, err
+	}
+	candidates := configList.Rank(selector)
+
+	// 依次尝试打开
+	return camera.OpenWithFallback(candidates, func(cfg camera.DeviceConfig) error {
+		if err := p.open(cameraInfo, &cfg); err != nil {
+			return err
+		}
+		if err := p.waitFrame(timeout); err != nil {
+			// 关闭无法出流的配置
+			p.close()
+			return err
+		}
+		p.deviceConfigList = configList
+		return nil
+	})
+}
+
+// 在已打开的相机上切换配置（无锁）
+//
+//	@param	cfg	新配置
+//	@return	配置变更（配置未改变时为nil）
+//	@return	异常信息
+func (p *Control) reconfigure(cfg camera.DeviceConfig) (*camera.ConfigChange, error) {
+	// 检查相机是否已打开
+	if p.handle == nil || p.deviceSupportInfo.IsZero() {
+		return nil, camera.ErrDeviceNotOpen
+	}
+
+	// 检查格式，避免无效的FOURCC以0传给相机库
+	if err := cfg.Format.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 使用打开时缓存的配置列表校验，无需重新枚举
+	yesInfo, err := p.deviceConfigList.Get(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if yesInfo.Eq(&p.deviceSupportInfo) {
+		return nil, nil
+	}
+
+	// 转换设备路径
+	devicePath := C.CString(p.deviceInfo.SymbolicLink)
+	defer C.free(unsafe.Pointer(devicePath))
+	// 使用指定配置打开设备
+	openDevice := func(info *camera.DeviceConfig) error {
+		var frameInfo C.VideoFrameInfo
+		frameInfo.width = C.uint32_t(info.Width)
+		frameInfo.height = C.uint32_t(info.Height)
+		frameInfo.fps = C.uint32_t(info.FrameInterval().FPS())
+		frameInfo.format = C.uint32_t(info.Format.Number())
+		return convertStatusCode(C.BecamOpenDevice(p.handle, devicePath, &frameInfo))
+	}
+
+	// 相机库没有单独的切换接口，直接关闭后以新配置打开（跳过close()中的等待与信息清理）
+	start := time.Now()
+	old := p.deviceSupportInfo
+	C.BecamCloseDevice(p.handle)
+	if err := openDevice(yesInfo); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		// 尝试恢复原配置
+		if restoreErr := openDevice(&old); restoreErr != nil {
+			fmt.Fprintln(os.Stderr, restoreErr.Error())
+			p.deviceInfo = camera.Device{}
+			p.deviceSupportInfo = camera.DeviceConfig{}
+			p.deviceConfigList = nil
+			p.streamCache.Reset()
+			return nil, errors.Join(camera.ErrDeviceOpenFailed, err, restoreErr)
+		}
+		return nil, errors.Join(camera.ErrDeviceOpenFailed, err)
+	}
+
+	// 更新当前配置，参数集随配置变化需要重新缓存
 	p.deviceSupportInfo = *yesInfo
+	p.streamCache.Reset()
+	p.stats.Reconfigures++
+	p.stats.LastReconfigure = time.Since(start)
+	return &camera.ConfigChange{
+		Device: p.deviceInfo,
+		Old:    old,
+		New:    *yesInfo,
+		Cost:   p.stats.LastReconfigure,
+	}, nil
+}
 
-	// 尝试获取帧
-	_, _, err = p.tryGetFrame()
+// Reconfigure 在不关闭相机的情况下切换配置
+//
+//	@param	cfg	新配置
+//	@return	异常信息
+func (p *Control) Reconfigure(cfg camera.DeviceConfig) error {
+	// 操作加锁
+	p.rwmutex.Lock()
+	change, err := p.reconfigure(cfg)
+	p.rwmutex.Unlock()
+
+	// 释放锁后通知订阅者，允许在回调中调用其它方法
+	if change != nil {
+		p.subscribers.Notify(*change)
+	}
 	return err
 }
 
+// SubscribeConfig 订阅配置变更
+//
+//	@param	fn	回调函数
+//	@return	取消订阅函数
+func (p *Control) SubscribeConfig(fn func(camera.ConfigChange)) func() {
+	return p.subscribers.Subscribe(fn)
+}
+
+// GetFrameStats 获取当前相机的取帧统计
+func (p *Control) GetFrameStats() camera.FrameStats {
+	// 操作加读锁
+	p.rwmutex.RLock()
+	defer p.rwmutex.RUnlock()
+
+	return p.stats
+}
+
 // GetStream 获取帧
 //
 //	@return	帧数据
@@ -416,6 +709,74 @@ func (p *Control) GetFrame() ([]byte, *camera.DeviceConfig, error) {
 	return p.tryGetFrame()
 }
 
+// GetStreamCache 获取压缩码流从最近关键帧开始的缓存帧
+//
+//	@return	缓存帧列表（尚未收到关键帧时为空）
+//	@return	异常信息
+func (p *Control) GetStreamCache() ([]*camera.Frame, error) {
+	// 操作加读锁
+	p.rwmutex.RLock()
+	defer p.rwmutex.RUnlock()
+
+	// 检查相机是否已打开
+	if p.handle == nil || p.deviceSupportInfo.IsZero() {
+		return nil, camera.ErrDeviceNotOpen
+	}
+	// 检查是否为压缩码流
+	if !camera.IsStreamCacheFormat(p.deviceSupportInfo.Format) {
+		return nil, errors.Join(camera.ErrUnsupportedFrameFormat, fmt.Errorf("%s is not a compressed stream", p.deviceSupportInfo.Format))
+	}
+
+	// 返回缓存帧
+	return p.streamCache.GOP(), nil
+}
+
+// SetCorruptFramePolicy 设置损坏帧处理策略（默认为CorruptFramePass）
+//
+//	@param	policy	损坏帧处理策略
+func (p *Control) SetCorruptFramePolicy(policy camera.CorruptFramePolicy) {
+	// 操作加锁
+	p.rwmutex.Lock()
+	defer p.rwmutex.Unlock()
+
+	p.corruptFramePolicy = policy
+}
+
+// SetListNodeTypes 设置GetList返回的节点类型（下次获取相机列表时生效）
+//
+//	@param	types	节点类型（为0时恢复为NodeCapture）
+func (p *Control) SetListNodeTypes(types camera.DeviceNodeType) {
+	if types == 0 {
+		types = camera.NodeCapture
+	}
+
+	// 操作加锁
+	p.rwmutex.Lock()
+	defer p.rwmutex.Unlock()
+
+	p.listNodeTypes = types
+}
+
+// SetDeviceAliases 设置设备别名
+//
+//	@param	aliases	设备别名列表（为nil时清除）
+//	@return	异常信息
+func (p *Control) SetDeviceAliases(aliases *camera.DeviceAliases) error {
+	// 检查别名规则
+	if aliases != nil {
+		if err := aliases.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// 操作加锁
+	p.rwmutex.Lock()
+	defer p.rwmutex.Unlock()
+
+	p.aliases = aliases
+	return nil
+}
+
 // 关闭已打开的相机（无锁）
 func (p *Control) close() {
 	// 是否存在已打开的相机
@@ -430,6 +791,8 @@ func (p *Control) close() {
 	// 清除当前使用的相机信息
 	p.deviceInfo = camera.Device{}
 	p.deviceSupportInfo = camera.DeviceConfig{}
+	p.deviceConfigList = nil
+	p.streamCache.Reset()
 }
 
 // Close 关闭已打开的相机
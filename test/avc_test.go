@@ -0,0 +1,166 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+// 构造Main档次1280x720的H.265 SPS
+func testHEVCSPS() []byte {
+	w := &bitWriter{}
+	w.u(8, 0x01)        // nal头第2字节
+	w.u(4, 0)           // sps_video_parameter_set_id
+	w.u(3, 0)           // sps_max_sub_layers_minus1
+	w.u(1, 1)           // sps_temporal_id_nesting_flag
+	w.u(2, 0)           // general_profile_space
+	w.u(1, 0)           // general_tier_flag
+	w.u(5, 1)           // general_profile_idc
+	w.u(32, 0x60000000) // general_profile_compatibility_flags
+	w.u(16, 0x9000)     // general constraint flags
+	w.u(32, 0)
+	w.u(8, 93) // general_level_idc
+	w.ue(0)    // sps_seq_parameter_set_id
+	w.ue(1)    // chroma_format_idc
+	w.ue(1280) // pic_width_in_luma_samples
+	w.ue(736)  // pic_height_in_luma_samples
+	w.u(1, 1)  // conformance_window_flag
+	w.ue(0)
+	w.ue(0)
+	w.ue(0)
+	w.ue(8)
+	w.ue(0) // bit_depth_luma_minus8
+	w.ue(0) // bit_depth_chroma_minus8
+	return w.nal(0x42)
+}
+
+func TestAnnexBLengthPrefixed(t *testing.T) {
+	nals := [][]byte{testH264SPS(), {0x68, 0xce, 0x3c, 0x80}, {0x65, 0x88, 0x84, 0x00, 0x00, 0x03, 0x01}}
+	annexB := bytes.Join([][]byte{nil, nals[0], nals[1], nals[2]}, []byte{0, 0, 0, 1})
+
+	for _, size := range []int{1, 2, 4} {
+		avc, err := camera.AnnexBToLengthPrefixed(annexB, size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := camera.SplitLengthPrefixed(avc, size)
+		if err != nil || len(got) != len(nals) {
+			t.Fatalf("split length %d: %d nal units, %v", size, len(got), err)
+		}
+		for i := range nals {
+			if !bytes.Equal(got[i], nals[i]) {
+				t.Errorf("length %d nal %d: %x, want %x", size, i, got[i], nals[i])
+			}
+		}
+		back, err := camera.LengthPrefixedToAnnexB(avc, size)
+		if err != nil || !bytes.Equal(back, annexB) {
+			t.Errorf("length %d round trip: %x, %v", size, back, err)
+		}
+	}
+
+	// 非法参数与截断数据
+	if _, err := camera.AnnexBToLengthPrefixed(annexB, 3); !errors.Is(err, camera.ErrInvalidParam) {
+		t.Errorf("expected ErrInvalidParam, got %v", err)
+	}
+	if _, err := camera.SplitLengthPrefixed([]byte{0, 0, 0, 9, 0x65}, 4); !errors.Is(err, camera.ErrParseBitstreamFailed) {
+		t.Errorf("expected ErrParseBitstreamFailed, got %v", err)
+	}
+
+	// 通过转换器完成封装转换
+	frame := &camera.Frame{Data: annexB, Config: camera.NewDeviceConfig(1920, 1080, 30, camera.FOURCC_H264)}
+	out, err := camera.Convert(frame, camera.FOURCC_H264_NO_SC)
+	if err != nil || out.Config.Format != camera.FOURCC_H264_NO_SC || out.Data[3] != byte(len(nals[0])) {
+		t.Errorf("convert to H264_NO_SC: %+v, %v", out, err)
+	}
+}
+
+func TestAVCDecoderConfig(t *testing.T) {
+	sps, pps := testH264SPS(), []byte{0x68, 0xce, 0x3c, 0x80}
+	data := bytes.Join([][]byte{nil, sps, pps, {0x65, 0x88}}, []byte{0, 0, 0, 1})
+
+	cfg, err := camera.NewAVCDecoderConfigFromAnnexB(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ProfileIdc != 100 || cfg.LevelIdc != 40 || cfg.LengthSize != 4 {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+	record, err := cfg.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{1, 100, 0, 40, 0xff, 0xe1, 0, byte(len(sps))}
+	if !bytes.HasPrefix(record, want) || !bytes.HasSuffix(record, []byte{0xfd, 0xf8, 0xf8, 0}) {
+		t.Errorf("avcC %x", record)
+	}
+
+	parsed, err := camera.ParseAVCDecoderConfig(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.SPS) != 1 || !bytes.Equal(parsed.SPS[0], sps) || len(parsed.PPS) != 1 || !bytes.Equal(parsed.PPS[0], pps) {
+		t.Errorf("parsed parameter sets %x, %x", parsed.SPS, parsed.PPS)
+	}
+	if parsed.ChromaFormatIdc != 1 || parsed.BitDepthLuma != 8 {
+		t.Errorf("parsed config %+v", parsed)
+	}
+	if got := parsed.AnnexBParameterSets(); !bytes.Equal(got, data[:len(data)-6]) {
+		t.Errorf("annex-b parameter sets %x", got)
+	}
+
+	if _, err := camera.NewAVCDecoderConfigFromAnnexB([]byte{0, 0, 1, 0x65, 0x88}); !errors.Is(err, camera.ErrInvalidParam) {
+		t.Errorf("expected ErrInvalidParam, got %v", err)
+	}
+	if _, err := camera.ParseAVCDecoderConfig(record[:10]); !errors.Is(err, camera.ErrParseBitstreamFailed) {
+		t.Errorf("expected ErrParseBitstreamFailed, got %v", err)
+	}
+}
+
+func TestHEVCDecoderConfig(t *testing.T) {
+	vps := []byte{0x40, 0x01, 0x0c, 0x01, 0xff, 0xff}
+	sps := testHEVCSPS()
+	pps := []byte{0x44, 0x01, 0xc1, 0x72, 0xb4, 0x62, 0x40}
+	idr := []byte{0x26, 0x01, 0xaf, 0x06}
+	data := bytes.Join([][]byte{nil, vps, sps, pps, idr}, []byte{0, 0, 0, 1})
+
+	nals := camera.ParseHEVCNALUnits(data)
+	if len(nals) != 4 || nals[1].Type != camera.HEVCNALSPS || !nals[3].Type.IsIRAP() {
+		t.Fatalf("unexpected nal units %+v", nals)
+	}
+	info, err := camera.ParseHEVCSPS(sps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Width != 1280 || info.Height != 720 || info.ProfileIdc != 1 || info.LevelIdc != 93 {
+		t.Errorf("unexpected sps %+v", info)
+	}
+
+	cfg, err := camera.NewHEVCDecoderConfigFromAnnexB(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := cfg.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{1, 0x01, 0x60, 0, 0, 0, 0x90, 0, 0, 0, 0, 0, 93}
+	if !bytes.HasPrefix(record, want) || record[21] != 0x0f || record[22] != 3 {
+		t.Errorf("hvcC %x", record)
+	}
+
+	parsed, err := camera.ParseHEVCDecoderConfig(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.SPS != cfg.SPS || parsed.LengthSize != 4 {
+		t.Errorf("parsed config %+v", parsed)
+	}
+	if got := parsed.AnnexBParameterSets(); !bytes.Equal(got, data[:len(data)-len(idr)-4]) {
+		t.Errorf("annex-b parameter sets %x", got)
+	}
+	if _, err := camera.ParseHEVCDecoderConfig(record[:30]); !errors.Is(err, camera.ErrParseBitstreamFailed) {
+		t.Errorf("expected ErrParseBitstreamFailed, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestAspectRatio(t *testing.T) {
+	cases := map[[2]uint32]string{
+		{1920, 1080}: "16:9",
+		{640, 480}:   "4:3",
+		{1280, 1024}: "5:4",
+		{1366, 768}:  "16:9",
+		{1920, 1200}: "8:5",
+		{2560, 1080}: "64:27",
+		{0, 480}:     "",
+	}
+	for res, want := range cases {
+		if got := camera.AspectRatio(res[0], res[1]); got != want {
+			t.Errorf("AspectRatio(%d, %d) = %q, want %q", res[0], res[1], got, want)
+		}
+	}
+}
+
+func TestDeviceConfigListCapabilities(t *testing.T) {
+	list := append(testConfigList(), &camera.DeviceConfig{Width: 1280, Height: 720, Interval: camera.FrameIntervalFromFPS(30), Format: camera.FOURCC_YUYV})
+	caps := list.Capabilities()
+
+	// 格式保持出现顺序
+	if len(caps.Formats) != 2 || caps.Formats[0].Format != camera.FOURCC_MJPEG || caps.Formats[1].Format != camera.FOURCC_YUYV {
+		t.Fatalf("unexpected formats\n%s", caps)
+	}
+
+	// 分辨率按像素数从大到小，帧率从高到低
+	mjpg := caps.Format(camera.FOURCC_MJPEG)
+	if len(mjpg.Resolutions) != 3 || mjpg.Resolutions[1].Width != 1280 || mjpg.Resolutions[1].Aspect != "16:9" {
+		t.Fatalf("unexpected MJPG resolutions\n%s", caps)
+	}
+	if rates := mjpg.Resolutions[1].FrameRates; len(rates) != 2 || rates[0].Interval.FPS() != 60 || rates[1].Interval.FPS() != 30 {
+		t.Errorf("unexpected 1280x720 frame rates %+v", rates)
+	}
+
+	// 最大分辨率
+	if w, h, err := list.MaxResolution(camera.FOURCC_YUYV); err != nil || w != 1920 || h != 1080 {
+		t.Errorf("MaxResolution(YUYV) = %dx%d, %v", w, h, err)
+	}
+	if _, _, err := list.MaxResolution(camera.FOURCC_NV12); err != camera.ErrDeviceMediaConfigNotFound {
+		t.Errorf("expected ErrDeviceMediaConfigNotFound, got %v", err)
+	}
+
+	// 宽高比
+	if got := list.AspectRatios(); !reflect.DeepEqual(got, []string{"16:9", "4:3"}) {
+		t.Errorf("AspectRatios() = %v", got)
+	}
+
+	// 带宽估算：YUYV 1280x720@30约55MB/s，超过USB 2.0等时传输上限
+	yuyv := caps.Format(camera.FOURCC_YUYV)
+	rate := yuyv.Resolutions[1].FrameRates[0]
+	if rate.Bandwidth != 1280*720*2*30 || !rate.ExceedsUSB2() {
+		t.Errorf("unexpected bandwidth %+v", rate)
+	}
+	if mjpg.Resolutions[0].FrameRates[0].ExceedsUSB2() {
+		t.Errorf("MJPG 1920x1080@30 should fit USB 2.0")
+	}
+	if s := caps.String(); !strings.Contains(s, "YUYV (max 1920x1080, 16 bpp)") || !strings.Contains(s, "30 fps 55.3 MB/s (exceeds USB 2.0)") {
+		t.Errorf("unexpected tree\n%s", s)
+	}
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestParseConfigQuery(t *testing.T) {
+	q, err := camera.ParseConfigQuery("1280x720@30 MJPG|YUYV aspect=16:9 minfps=25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Width != 1280 || q.Height != 720 || q.FPS != camera.FrameIntervalFromFPS(30) || q.MinFPS != camera.FrameIntervalFromFPS(25) || len(q.Formats) != 2 || q.Formats[1] != camera.FOURCC_YUYV {
+		t.Errorf("unexpected query %+v", q)
+	}
+	if got := q.String(); got != "1280x720@30 MJPG|YUYV aspect=16:9 minfps=25" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// 不足4字符的格式名以空格补齐
+	q, err = camera.ParseConfigQuery("max-res Y16 maxres=1920x1080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.BestRes || len(q.Formats) != 1 || q.Formats[0] != camera.FOURCC_Y16 || q.MaxRes != [2]uint32{1920, 1080} {
+		t.Errorf("unexpected query %+v", q)
+	}
+
+	for _, query := range []string{"1280x@30", "@0", "aspect=16:0", "MJPEG", "fps=30", "MJPG||YUYV"} {
+		if _, err := camera.ParseConfigQuery(query); !errors.Is(err, camera.ErrInvalidParam) {
+			t.Errorf("%q: expected ErrInvalidParam, got %v", query, err)
+		}
+	}
+}
+
+func TestDeviceConfigListQuery(t *testing.T) {
+	list := append(testConfigList(),
+		&camera.DeviceConfig{Width: 2592, Height: 1944, Interval: camera.FrameIntervalFromFPS(15), Format: camera.FOURCC_NV12},
+		&camera.DeviceConfig{Width: 1920, Height: 1080, Interval: camera.FrameIntervalFromFPS(30), Format: camera.FOURCC_NV12},
+	)
+
+	cases := []struct {
+		query string
+		want  camera.DeviceConfig
+	}{
+		{"1280x720@30 MJPG|YUYV aspect=16:9 minfps=25", camera.NewDeviceConfig(1280, 720, 30, camera.FOURCC_MJPEG)},
+		// YUYV帧率不足时退回MJPG，满足帧率后带宽更低的30帧优先
+		{"1280x720 YUYV|MJPG minfps=25", camera.NewDeviceConfig(1280, 720, 30, camera.FOURCC_MJPEG)},
+		{"max-res NV12", camera.NewDeviceConfig(2592, 1944, 15, camera.FOURCC_NV12)},
+		{"max-res NV12 aspect=16:9", camera.NewDeviceConfig(1920, 1080, 30, camera.FOURCC_NV12)},
+		{"max-fps MJPG", camera.NewDeviceConfig(1280, 720, 60, camera.FOURCC_MJPEG)},
+		{"YUYV maxres=1280x720 max-res", camera.NewDeviceConfig(1280, 720, 10, camera.FOURCC_YUYV)},
+	}
+	for _, c := range cases {
+		res, err := list.Query(c.query)
+		if err != nil {
+			t.Errorf("%q: %v", c.query, err)
+			continue
+		}
+		if !res.Eq(&c.want) {
+			t.Errorf("%q: got %+v, want %+v", c.query, res, c.want)
+		}
+	}
+
+	if _, err := list.Query("YUYV minfps=60"); !errors.Is(err, camera.ErrDeviceMediaConfigNotFound) {
+		t.Errorf("expected ErrDeviceMediaConfigNotFound, got %v", err)
+	}
+}
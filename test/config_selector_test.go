@@ -0,0 +1,95 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+// 测试用配置列表
+func testConfigList() camera.DeviceConfigList {
+	return camera.DeviceConfigList{
+		{Width: 1920, Height: 1080, Interval: camera.FrameIntervalFromFPS(30), Format: camera.FOURCC_MJPEG},
+		{Width: 1280, Height: 720, Interval: camera.FrameIntervalFromFPS(60), Format: camera.FOURCC_MJPEG},
+		{Width: 1280, Height: 720, Interval: camera.FrameIntervalFromFPS(30), Format: camera.FOURCC_MJPEG},
+		{Width: 640, Height: 480, Interval: camera.FrameIntervalFromFPS(30), Format: camera.FOURCC_MJPEG},
+		{Width: 1920, Height: 1080, Interval: camera.FrameIntervalFromFPS(5), Format: camera.FOURCC_YUYV},
+		{Width: 1280, Height: 720, Interval: camera.FrameIntervalFromFPS(10), Format: camera.FOURCC_YUYV},
+		{Width: 640, Height: 480, Interval: camera.FrameIntervalFromFPS(30), Format: camera.FOURCC_YUYV},
+	}
+}
+
+func TestConfigSelectorRank(t *testing.T) {
+	list := testConfigList()
+
+	// 期望1600x900@30：MJPG 1920x1080@30优于更小的分辨率，YUYV因帧率不足排在后面
+	res := list.Rank(&camera.ConfigSelector{
+		Width:   1600,
+		Height:  900,
+		FPS:     30,
+		Formats: []camera.Fourcc{camera.FOURCC_YUYV, camera.FOURCC_MJPEG},
+	})
+	if len(res) != len(list) {
+		t.Fatalf("expected %d candidates, got %d", len(list), len(res))
+	}
+	want := camera.NewDeviceConfig(1920, 1080, 30, camera.FOURCC_MJPEG)
+	if !res[0].Config.Eq(&want) {
+		t.Errorf("best candidate %s", res[0].Explain())
+	}
+	for i := 1; i < len(res); i++ {
+		if res[i].Score < res[i-1].Score {
+			t.Errorf("candidates not sorted: %s before %s", res[i-1].Explain(), res[i].Explain())
+		}
+	}
+	if exp := res[0].Explain(); !strings.Contains(exp, "resolution 1920x1080 vs 1600x900") || !strings.Contains(exp, "fps 30 vs 30") {
+		t.Errorf("unexpected explanation %q", exp)
+	}
+
+	// 只接受YUYV时在分辨率与帧率之间权衡
+	best, err := list.GetBest(&camera.ConfigSelector{Width: 1280, Height: 720, FPS: 30, Formats: []camera.Fourcc{camera.FOURCC_YUYV}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if best.Config.Format != camera.FOURCC_YUYV {
+		t.Errorf("unexpected format %s", best.Explain())
+	}
+
+	// 提高帧率权重后优先保证帧率
+	weights := camera.DefaultConfigWeights
+	weights.FPS = 10
+	best, _ = list.GetBest(&camera.ConfigSelector{Width: 1280, Height: 720, FPS: 30, Formats: []camera.Fourcc{camera.FOURCC_YUYV}, Weights: weights})
+	if want := camera.NewDeviceConfig(640, 480, 30, camera.FOURCC_YUYV); !best.Config.Eq(&want) {
+		t.Errorf("expected 640x480@30 YUYV, got %s", best.Explain())
+	}
+
+	// 格式不存在
+	if _, err := list.GetBest(&camera.ConfigSelector{Formats: []camera.Fourcc{camera.FOURCC_NV12}}); err != camera.ErrDeviceMediaConfigNotFound {
+		t.Errorf("expected ErrDeviceMediaConfigNotFound, got %v", err)
+	}
+}
+
+func TestGetMostSimilar(t *testing.T) {
+	list := testConfigList()
+
+	// 宽度大于但高度小于目标的配置也参与比较
+	res, err := list.GetMostSimilar(camera.NewDeviceConfig(1000, 1000, 30, camera.FOURCC_MJPEG), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := camera.NewDeviceConfig(1280, 720, 30, camera.FOURCC_MJPEG); !res.Eq(&want) {
+		t.Errorf("unexpected config %+v", res)
+	}
+
+	// 目标格式不存在时使用备用目标
+	res, err = list.GetMostSimilar(
+		camera.NewDeviceConfig(1280, 720, 30, camera.FOURCC_NV12),
+		[]camera.DeviceConfig{camera.NewDeviceConfig(640, 480, 30, camera.FOURCC_YUYV)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := camera.NewDeviceConfig(640, 480, 30, camera.FOURCC_YUYV); !res.Eq(&want) {
+		t.Errorf("unexpected standby config %+v", res)
+	}
+}
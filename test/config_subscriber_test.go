@@ -0,0 +1,43 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestConfigSubscribers(t *testing.T) {
+	var subs camera.ConfigSubscribers
+	change := camera.ConfigChange{
+		Old: camera.NewDeviceConfig(1920, 1080, 30, camera.FOURCC_MJPEG),
+		New: camera.NewDeviceConfig(640, 480, 30, camera.FOURCC_YUYV),
+	}
+
+	// 按订阅顺序通知
+	var order []int
+	cancel1 := subs.Subscribe(func(c camera.ConfigChange) {
+		if c.New != change.New || c.Old != change.Old {
+			t.Errorf("unexpected change %+v", c)
+		}
+		order = append(order, 1)
+	})
+	// 回调中取消订阅不会死锁
+	var cancel2 func()
+	cancel2 = subs.Subscribe(func(camera.ConfigChange) {
+		order = append(order, 2)
+		cancel2()
+	})
+	subs.Notify(change)
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Fatalf("unexpected notify order %v", order)
+	}
+
+	// 取消订阅后不再通知，重复取消无影响
+	cancel1()
+	cancel1()
+	order = nil
+	subs.Notify(change)
+	if len(order) != 0 || subs.Len() != 0 {
+		t.Errorf("expected no subscribers, got %v, %d", order, subs.Len())
+	}
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"reflect"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestConvertPath(t *testing.T) {
+	cases := []struct {
+		from, to camera.Fourcc
+		want     []camera.Fourcc
+	}{
+		{camera.FOURCC_MJPEG, camera.FOURCC_RGBA32, []camera.Fourcc{camera.FOURCC_MJPEG, camera.FOURCC_YUV420, camera.FOURCC_RGBA32}},
+		{camera.FOURCC_NV12, camera.FOURCC_YVU420, []camera.Fourcc{camera.FOURCC_NV12, camera.FOURCC_YUV420, camera.FOURCC_YVU420}},
+		{camera.FOURCC_SBGGR8, camera.FOURCC_NV12, []camera.Fourcc{camera.FOURCC_SBGGR8, camera.FOURCC_RGB24, camera.FOURCC_RGBA32, camera.FOURCC_YUV420, camera.FOURCC_NV12}},
+	}
+	for _, c := range cases {
+		got, err := camera.ConvertPath(c.from, c.to)
+		if err != nil {
+			t.Fatal(c.from, err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s -> %s: got %v, want %v", c.from, c.to, got, c.want)
+		}
+	}
+
+	if _, err := camera.ConvertPath(camera.FOURCC_RGBA32, camera.FOURCC_MJPEG); !errors.Is(err, camera.ErrConvertPathNotFound) {
+		t.Errorf("expected ErrConvertPathNotFound, got %v", err)
+	}
+}
+
+func TestConvertChain(t *testing.T) {
+	// 2x2 YUYV，白色与黑色交替
+	frame := &camera.Frame{
+		Data:   []byte{235, 128, 16, 128, 16, 128, 235, 128},
+		Config: camera.DeviceConfig{Width: 2, Height: 2, Format: camera.FOURCC_YUYV},
+	}
+	res, err := camera.Convert(frame, camera.FOURCC_NV12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{235, 16, 16, 235, 128, 128}
+	if res.Config.Format != camera.FOURCC_NV12 || !bytes.Equal(res.Data, want) {
+		t.Errorf("got %s %v, want %v", res.Config.Format, res.Data, want)
+	}
+
+	// 同格式直接返回
+	if same, _ := camera.Convert(res, camera.FOURCC_NV12); same != res {
+		t.Error("same format should return the source frame")
+	}
+}
+
+func TestConvertJPEG(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	for i := range src.Pix {
+		src.Pix[i] = 0xff
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	}
+
+	frame := &camera.Frame{Data: buf.Bytes(), Config: camera.DeviceConfig{Format: camera.FOURCC_MJPEG}}
+	res, err := camera.Convert(frame, camera.FOURCC_RGBA32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Config.Width != 16 || res.Config.Height != 16 {
+		t.Fatalf("unexpected size %dx%d", res.Config.Width, res.Config.Height)
+	}
+	if px := res.Data[:4]; px[0] < 0xf8 || px[3] != 0xff {
+		t.Errorf("white pixel decoded as %v", px)
+	}
+
+	frame.Data = []byte{0xff, 0xd8, 0x00}
+	if _, err := camera.Convert(frame, camera.FOURCC_RGBA32); !errors.Is(err, camera.ErrDecodeJpegImageFailed) {
+		t.Errorf("expected ErrDecodeJpegImageFailed, got %v", err)
+	}
+}
+
+func TestRegisterConverter(t *testing.T) {
+	const custom = camera.Fourcc("TST0")
+	camera.RegisterConverter(custom, camera.FOURCC_GREY, 1, func(src *camera.Frame) (*camera.Frame, error) {
+		return &camera.Frame{Data: []byte{0xff}, Config: src.Config}, nil
+	})
+	frame := &camera.Frame{Data: []byte{0}, Config: camera.DeviceConfig{Width: 1, Height: 1, Format: custom}}
+	res, err := camera.Convert(frame, camera.FOURCC_RGBA32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := (color.RGBA{res.Data[0], res.Data[1], res.Data[2], res.Data[3]}); got != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestConvertPassThrough(t *testing.T) {
+	const from, to = camera.Fourcc("TST1"), camera.Fourcc("TST2")
+	camera.RegisterConverter(from, to, 1, func(src *camera.Frame) (*camera.Frame, error) {
+		return src, nil
+	})
+	frame := &camera.Frame{Data: []byte{1, 2}, Config: camera.DeviceConfig{Width: 1, Height: 1, Format: from}}
+	res, err := camera.Convert(frame, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 原样返回源帧的转换函数不会修改调用方的帧
+	if res.Config.Format != to || frame.Config.Format != from || res == frame {
+		t.Errorf("source frame modified: %q, result %q", frame.Config.Format, res.Config.Format)
+	}
+}
@@ -0,0 +1,45 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestDepthFrame(t *testing.T) {
+	// 2x2 深度帧：1000, 0, 2000, 3000（毫米）
+	data := []byte{0xe8, 0x03, 0x00, 0x00, 0xd0, 0x07, 0xb8, 0x0b}
+	depth, err := camera.NewDepthFrame(data, &camera.DeviceConfig{Width: 2, Height: 2, Format: camera.FOURCC_Z16}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := depth.Meters(0, 1); m != 2 {
+		t.Errorf("depth(0,1) = %v, want 2", m)
+	}
+
+	// 空洞填充
+	if v := depth.FillHoles(camera.HoleFillFromLeft).At(1, 0); v != 1000 {
+		t.Errorf("fill from left = %d", v)
+	}
+
+	// 无效像素渲染为黑色，有效像素不应为黑色
+	img := depth.Colorize(camera.ColormapTurbo, 0, 0)
+	if c := img.RGBAAt(1, 0); c.R|c.G|c.B != 0 {
+		t.Errorf("hole colour = %v", c)
+	}
+	if c := img.RGBAAt(1, 1); c.R|c.G|c.B == 0 {
+		t.Errorf("far colour = %v", c)
+	}
+
+	// 点云只包含有效像素
+	var buf bytes.Buffer
+	err = depth.WritePLY(&buf, camera.Intrinsics{Fx: 1, Fy: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := "ply\nformat binary_little_endian 1.0\nelement vertex 3\n"
+	if !bytes.HasPrefix(buf.Bytes(), []byte(header)) || !bytes.HasSuffix(buf.Bytes()[:buf.Len()-36], []byte("end_header\n")) {
+		t.Errorf("unexpected ply output: %q", buf.String())
+	}
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+// 测试用相机列表
+func testAliasDeviceList() camera.DeviceList {
+	return camera.DeviceList{
+		{ID: "a", LegacyID: "legacy-a", Name: "HD USB Camera", Identity: camera.DeviceIdentity{VendorID: "046d", ProductID: "0825", Serial: "ABC123", PortPath: "usb-1-2"}},
+		{ID: "b", Name: "HD USB Camera", Identity: camera.DeviceIdentity{VendorID: "046d", ProductID: "0825", Serial: "XYZ789", PortPath: "usb-1-3"}},
+		{ID: "c", Name: "Integrated Webcam", Identity: camera.DeviceIdentity{VendorID: "0bda", ProductID: "58f4"}},
+	}
+}
+
+func TestDeviceAliasesLookup(t *testing.T) {
+	list := testAliasDeviceList()
+	aliases := &camera.DeviceAliases{}
+	for alias, rule := range map[string]camera.DeviceMatchRule{
+		"front-door": {Serial: "ABC123"},
+		"back-door":  {Name: "^HD USB", PortPath: "usb-1-3"},
+		"laptop":     {VIDPID: "0BDA:58F4"},
+		"any-hd":     {Name: "^HD USB"},
+		"missing":    {Serial: "NOPE"},
+	} {
+		if err := aliases.Set(alias, rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for id, want := range map[string]string{"front-door": "a", "back-door": "b", "laptop": "c", "c": "c", "legacy-a": "a"} {
+		dev, err := aliases.Lookup(list, id)
+		if err != nil || dev.ID != want {
+			t.Errorf("Lookup(%q) = %+v, %v, want %s", id, dev, err, want)
+		}
+	}
+	if _, err := aliases.Lookup(list, "any-hd"); err != camera.ErrDeviceAliasAmbiguous {
+		t.Errorf("expected ErrDeviceAliasAmbiguous, got %v", err)
+	}
+	for _, id := range []string{"missing", "unknown"} {
+		if _, err := aliases.Lookup(list, id); err != camera.ErrDeviceNotFound {
+			t.Errorf("%q: expected ErrDeviceNotFound, got %v", id, err)
+		}
+	}
+
+	// 未设置别名时仅按ID查询
+	var none *camera.DeviceAliases
+	if dev, err := none.Lookup(list, "b"); err != nil || dev.ID != "b" {
+		t.Errorf("nil aliases lookup: %+v, %v", dev, err)
+	}
+
+	// 无效规则不会被添加
+	for _, rule := range []camera.DeviceMatchRule{{}, {Name: "("}, {VIDPID: "046d"}} {
+		if err := aliases.Set("bad", rule); !errors.Is(err, camera.ErrInvalidParam) {
+			t.Errorf("%+v: expected ErrInvalidParam, got %v", rule, err)
+		}
+	}
+	if _, err := aliases.Lookup(list, "bad"); err != camera.ErrDeviceNotFound {
+		t.Errorf("expected invalid alias to be rejected, got %v", err)
+	}
+}
+
+func TestDeviceAliasesFile(t *testing.T) {
+	dir := t.TempDir()
+	aliases := &camera.DeviceAliases{}
+	if err := aliases.Set("front-door", camera.DeviceMatchRule{Serial: "ABC123", VIDPID: "046d:0825"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"aliases.yaml", "aliases.json"} {
+		path := filepath.Join(dir, name)
+		if err := aliases.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := camera.LoadDeviceAliases(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(loaded.Aliases) != 1 || loaded.Aliases[0] != aliases.Aliases[0] {
+			t.Errorf("%s: unexpected aliases %+v", name, loaded.Aliases)
+		}
+	}
+
+	// 手写的YAML
+	path := filepath.Join(dir, "manual.yml")
+	data := "aliases:\n  - alias: back-door\n    match:\n      name: ^HD USB\n      port_path: usb-1-3\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := camera.LoadDeviceAliases(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev, err := loaded.Lookup(testAliasDeviceList(), "back-door"); err != nil || dev.ID != "b" {
+		t.Errorf("Lookup(back-door) = %+v, %v", dev, err)
+	}
+
+	// 重复别名
+	data = "aliases:\n  - alias: x\n    match: {serial: a}\n  - alias: x\n    match: {serial: b}\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := camera.LoadDeviceAliases(path); !errors.Is(err, camera.ErrInvalidParam) {
+		t.Errorf("expected ErrInvalidParam, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFourccText(t *testing.T) {
+	text, err := camera.FOURCC_MJPEG.MarshalText()
+	if err != nil || string(text) != "MJPG" {
+		t.Fatalf("MarshalText: %q, %v", text, err)
+	}
+	var f camera.Fourcc
+	if err := f.UnmarshalText([]byte("YUYV")); err != nil || f != camera.FOURCC_YUYV {
+		t.Errorf("UnmarshalText: %q, %v", f, err)
+	}
+
+	for _, s := range []string{"MJP", "MJPEG", "MJ\x00G"} {
+		if err := f.UnmarshalText([]byte(s)); !errors.Is(err, camera.ErrInvalidParam) {
+			t.Errorf("%q: expected ErrInvalidParam, got %v", s, err)
+		}
+	}
+
+	// 未设置的FOURCC输出为空字符串并可还原
+	if text, err := camera.Fourcc("").MarshalText(); err != nil || len(text) != 0 {
+		t.Errorf("empty MarshalText: %q, %v", text, err)
+	}
+	if err := f.UnmarshalText(nil); err != nil || f != "" {
+		t.Errorf("empty UnmarshalText: %q, %v", f, err)
+	}
+}
+
+func TestDeviceConfigText(t *testing.T) {
+	cfg := camera.NewDeviceConfig(1920, 1080, 30, camera.FOURCC_MJPEG)
+	if cfg.String() != "MJPG 1920x1080@30" {
+		t.Errorf("String() = %q", cfg.String())
+	}
+	for _, c := range []camera.DeviceConfig{cfg, camera.NewDeviceConfig(640, 480, 15, camera.FOURCC_Y16)} {
+		res, err := camera.ParseDeviceConfig(c.String())
+		if err != nil || res != c {
+			t.Errorf("%q round trip: %+v, %v", c.String(), res, err)
+		}
+	}
+	for _, s := range []string{"MJPG", "MJPG 1920x1080", "MJPEG 1920x1080@30", "MJPG 1920*1080@30", "MJPG 1920x1080@-1"} {
+		if _, err := camera.ParseDeviceConfig(s); !errors.Is(err, camera.ErrInvalidParam) {
+			t.Errorf("%q: expected ErrInvalidParam, got %v", s, err)
+		}
+	}
+}
+
+func TestDeviceConfigJSONAndYAML(t *testing.T) {
+	type settings struct {
+		Camera camera.DeviceConfig   `json:"camera" yaml:"camera"`
+		Backup []camera.DeviceConfig `json:"backup" yaml:"backup"`
+	}
+	want := settings{
+		Camera: camera.NewDeviceConfig(1920, 1080, 30, camera.FOURCC_MJPEG),
+		Backup: []camera.DeviceConfig{camera.NewDeviceConfig(640, 480, 30, camera.FOURCC_YUYV)},
+	}
+
+	// JSON
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"camera":{"width":1920,"height":1080,"interval":"1/30","format":"MJPG"},"backup":[{"width":640,"height":480,"interval":"1/30","format":"YUYV"}]}` {
+		t.Errorf("json %s", data)
+	}
+	var got settings
+	// 兼容字符串与整数帧率写法
+	if err := json.Unmarshal([]byte(`{"camera":"MJPG 1920x1080@30","backup":[{"width":640,"height":480,"fps":30,"format":"YUYV"}]}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Camera != want.Camera || len(got.Backup) != 1 || got.Backup[0] != want.Backup[0] {
+		t.Errorf("json decoded %+v", got)
+	}
+	// 对象形式与字符串形式一样校验格式、宽高与帧率
+	for _, data := range []string{
+		`{"camera":{"width":1,"height":1,"interval":"1/1","format":"BAD"}}`,
+		`{"camera":{"width":1920,"height":1080,"interval":"1/30"}}`,
+		`{"camera":{"width":0,"height":1080,"interval":"1/30","format":"MJPG"}}`,
+		`{"camera":{"width":1920,"interval":"1/30","format":"MJPG"}}`,
+		`{"camera":{"width":1920,"height":1080,"format":"MJPG"}}`,
+	} {
+		if err := json.Unmarshal([]byte(data), &got); !errors.Is(err, camera.ErrInvalidParam) {
+			t.Errorf("%s: expected ErrInvalidParam, got %v", data, err)
+		}
+	}
+
+	// 未设置的配置输出为null
+	type unset struct {
+		Camera camera.DeviceConfig `json:"camera" yaml:"camera"`
+		Format camera.Fourcc       `json:"format" yaml:"format"`
+	}
+	if data, err := json.Marshal(unset{}); err != nil || string(data) != `{"camera":null,"format":""}` {
+		t.Errorf("zero json %s, %v", data, err)
+	}
+	if data, err := yaml.Marshal(unset{}); err != nil || string(data) != "camera: null\nformat: \"\"\n" {
+		t.Errorf("zero yaml %q, %v", data, err)
+	}
+
+	// null保持原值不变，未设置的配置可往返
+	kept := unset{Camera: want.Camera}
+	if err := json.Unmarshal([]byte(`{"camera":null}`), &kept); err != nil || kept.Camera != want.Camera {
+		t.Errorf("json null: %+v, %v", kept, err)
+	}
+	for _, data := range []string{"camera: null\n", "camera: ~\n", "camera:\n"} {
+		if err := yaml.Unmarshal([]byte(data), &kept); err != nil || kept.Camera != want.Camera {
+			t.Errorf("yaml %q: %+v, %v", data, kept, err)
+		}
+	}
+	var zero unset
+	if data, err := json.Marshal(zero); err != nil || json.Unmarshal(data, &zero) != nil || zero != (unset{}) {
+		t.Errorf("zero json round trip %s, %v", data, err)
+	}
+
+	// YAML
+	data, err = yaml.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = settings{}
+	if err := yaml.Unmarshal(data, &got); err != nil || got.Camera != want.Camera || got.Backup[0] != want.Backup[0] {
+		t.Errorf("yaml round trip %+v, %v\n%s", got, err, data)
+	}
+	for _, data := range []string{
+		"camera: {width: 1920, height: 1080, interval: 1/30}\n",
+		"camera: {width: 1920, height: 0, interval: 1/30, format: MJPG}\n",
+		"camera: {width: 1920, height: 1080, format: MJPG}\n",
+	} {
+		if err := yaml.Unmarshal([]byte(data), &got); !errors.Is(err, camera.ErrInvalidParam) {
+			t.Errorf("%q: expected ErrInvalidParam, got %v", data, err)
+		}
+	}
+	got = settings{}
+	if err := yaml.Unmarshal([]byte("camera: MJPG 1920x1080@30\nbackup:\n  - YUYV 640x480@30\n"), &got); err != nil || got.Camera != want.Camera || got.Backup[0] != want.Backup[0] {
+		t.Errorf("yaml decoded %+v, %v", got, err)
+	}
+}
+
+func TestFrameInterval(t *testing.T) {
+	cases := []struct {
+		rate     string
+		interval camera.FrameInterval
+		fps      uint32
+	}{
+		{"30", camera.NewFrameInterval(1, 30), 30},
+		{"29.97", camera.NewFrameInterval(1001, 30000), 30},
+		{"59.94", camera.NewFrameInterval(1001, 60000), 60},
+		{"23.976", camera.NewFrameInterval(1001, 24000), 24},
+		{"7.5", camera.NewFrameInterval(2, 15), 8},
+		{"0.5", camera.NewFrameInterval(2, 1), 1},
+		{"10/3", camera.NewFrameInterval(3, 10), 3},
+	}
+	for _, c := range cases {
+		interval, err := camera.ParseFrameRate(c.rate)
+		if err != nil || interval != c.interval {
+			t.Errorf("ParseFrameRate(%q) = %v, %v, want %v", c.rate, interval, err, c.interval)
+		}
+		if got := c.interval.RateString(); got != c.rate {
+			t.Errorf("%v RateString() = %q, want %q", c.interval, got, c.rate)
+		}
+		if got := c.interval.FPS(); got != c.fps {
+			t.Errorf("%v FPS() = %d, want %d", c.interval, got, c.fps)
+		}
+	}
+	if _, err := camera.ParseFrameRate("0"); !errors.Is(err, camera.ErrInvalidParam) {
+		t.Errorf("expected ErrInvalidParam, got %v", err)
+	}
+
+	// NTSC帧率不再与整数帧率合并
+	ntsc := camera.NewDeviceConfigWithInterval(1920, 1080, camera.NewFrameInterval(1001, 30000), camera.FOURCC_MJPEG)
+	whole := camera.NewDeviceConfig(1920, 1080, 30, camera.FOURCC_MJPEG)
+	if ntsc.Eq(&whole) || ntsc.FPS != whole.FPS || ntsc.Interval.Cmp(whole.Interval) >= 0 {
+		t.Errorf("unexpected comparison between %v and %v", ntsc, whole)
+	}
+	res, err := camera.ParseDeviceConfig(ntsc.String())
+	if err != nil || !res.Eq(&ntsc) || ntsc.String() != "MJPG 1920x1080@29.97" {
+		t.Errorf("%q round trip: %v, %v", ntsc.String(), res, err)
+	}
+
+	// 只填写FPS字段的旧代码按整数帧率处理
+	legacy := camera.DeviceConfig{Width: 1920, Height: 1080, FPS: 30, Format: camera.FOURCC_MJPEG}
+	if !legacy.Eq(&whole) || legacy.FrameInterval() != whole.Interval || legacy.String() != whole.String() {
+		t.Errorf("legacy config %v does not match %v", legacy, whole)
+	}
+	if whole.FPS != 30 || ntsc.FPS != 30 {
+		t.Errorf("unexpected FPS fields %d, %d", whole.FPS, ntsc.FPS)
+	}
+
+	// 复制配置后只修改FPS字段时以FPS为准
+	changed := whole
+	changed.FPS = 15
+	slow := camera.NewDeviceConfig(1920, 1080, 15, camera.FOURCC_MJPEG)
+	if changed.FrameInterval() != slow.Interval || !changed.Eq(&slow) || changed.Eq(&whole) {
+		t.Errorf("changed FPS config %v does not match %v", changed, slow)
+	}
+	changed = ntsc
+	changed.FPS = 30
+	if changed.FrameInterval() != ntsc.Interval {
+		t.Errorf("consistent FPS overrode interval: %v", changed.FrameInterval())
+	}
+	onlyInterval := camera.DeviceConfig{Width: 1920, Height: 1080, Interval: ntsc.Interval, Format: camera.FOURCC_MJPEG}
+	if !onlyInterval.Eq(&ntsc) {
+		t.Errorf("interval-only config %v does not match %v", onlyInterval, ntsc)
+	}
+
+	// 整数帧率合并后的重复配置
+	collapsed := camera.DeviceConfigList{&whole, &legacy, &ntsc}
+	if res := collapsed.Dedupe(); len(res) != 2 || res[0] != &whole || res[1] != &ntsc {
+		t.Errorf("unexpected dedupe result %v", res)
+	}
+
+	// 查询语句中的分数帧率
+	list := camera.DeviceConfigList{&whole, &ntsc}
+	if cfg, err := list.Query("MJPG maxfps=29.97"); err != nil || !cfg.Eq(&ntsc) {
+		t.Errorf("maxfps=29.97 resolved to %v, %v", cfg, err)
+	}
+	if cfg, err := list.Query("1920x1080@30 MJPG"); err != nil || !cfg.Eq(&whole) {
+		t.Errorf("@30 resolved to %v, %v", cfg, err)
+	}
+}
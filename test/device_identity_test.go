@@ -0,0 +1,217 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+// 创建伪造的/dev与/sys目录树
+//
+//	@param	root	伪造的根目录
+//	@param	usb		USB设备目录名称（如"1-2"）
+//	@param	node	video节点名称（如"video0"）
+//	@param	serial	USB序列号（为空时不写入）
+//	@param	index	节点序号
+func writeFakeV4L2(t *testing.T, root, usb, node, serial string, index int) {
+	t.Helper()
+	write := func(name, data string) {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := func(target, name string) {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usbDir := "sys/devices/pci0000:00/0000:00:14.0/usb1/" + usb
+	nodeDir := usbDir + "/" + usb + ":1.0/video4linux/" + node
+	write(usbDir+"/idVendor", "046d\n")
+	write(usbDir+"/idProduct", "0825\n")
+	if serial != "" {
+		write(usbDir+"/serial", serial+"\n")
+	}
+	write(nodeDir+"/index", string(rune('0'+index))+"\n")
+	link("../../../"+usb+":1.0", nodeDir+"/device")
+	link("../../"+nodeDir[len("sys/"):], "sys/class/video4linux/"+node)
+	write("dev/"+node, "")
+	link("../../"+node, "dev/v4l/by-path/pci-0000:00:14.0-usb-0:"+usb[2:]+":1.0-video-index"+string(rune('0'+index)))
+}
+
+func TestResolveDeviceIdentity(t *testing.T) {
+	root := t.TempDir()
+	writeFakeV4L2(t, root, "1-2", "video0", "ABC123", 0)
+	writeFakeV4L2(t, root, "1-3", "video2", "", 0)
+
+	id := camera.ResolveDeviceIdentity(root, "/dev/video0")
+	want := camera.DeviceIdentity{
+		VendorID:  "046d",
+		ProductID: "0825",
+		Serial:    "ABC123",
+		PortPath:  "pci-0000:00:14.0-usb-0:2:1.0-video-index0",
+	}
+	if id != want {
+		t.Fatalf("unexpected identity %+v", id)
+	}
+
+	// 同一台相机重新编号后ID不变
+	renumbered := t.TempDir()
+	writeFakeV4L2(t, renumbered, "1-4", "video4", "ABC123", 0)
+	id2 := camera.ResolveDeviceIdentity(renumbered, "/dev/video4")
+	if id.StableID() == "" || id.StableID() != id2.StableID() {
+		t.Errorf("expected stable id across renumbering: %+v, %+v", id, id2)
+	}
+
+	// 没有序列号时使用端口路径
+	noSerial := camera.ResolveDeviceIdentity(root, "/dev/video2")
+	if noSerial.Serial != "" || noSerial.PortPath == "" || noSerial.StableID() == id.StableID() {
+		t.Errorf("unexpected identity %+v", noSerial)
+	}
+
+	// 不存在的节点
+	if res := camera.ResolveDeviceIdentity(root, "/dev/video9"); !res.IsZero() {
+		t.Errorf("expected zero identity, got %+v", res)
+	}
+}
+
+func TestParseWindowsInstancePath(t *testing.T) {
+	link := `\\?\usb#vid_046d&pid_0825&mi_00#6&2f6bb8b2&0&0000#{e5323777-f976-4f5b-9b55-b94699c46e44}\global`
+	if got := camera.ParseWindowsInstancePath(link); got != `USB\VID_046D&PID_0825&MI_00\6&2F6BB8B2&0&0000` {
+		t.Errorf("unexpected instance path %q", got)
+	}
+	id := camera.ResolveDeviceIdentity("/", link)
+	if id.VIDPID() != "046d:0825" || id.StableID() == "" {
+		t.Errorf("unexpected identity %+v", id)
+	}
+	if camera.ParseWindowsInstancePath("/dev/video0") != "" {
+		t.Error("expected empty instance path")
+	}
+}
+
+func TestDeviceIDCompatibility(t *testing.T) {
+	identity := camera.DeviceIdentity{VendorID: "046d", ProductID: "0825", Serial: "K7F2Q9", PortPath: "usb-1-2"}
+	list := camera.DeviceList{
+		{Name: "USB Camera", SymbolicLink: "/dev/video0", Identity: identity},
+		{Name: "USB Camera", SymbolicLink: "/dev/video2", Identity: identity},
+		{Name: "Virtual", SymbolicLink: "/dev/video4"},
+	}
+	list[1].Identity.PortPath = "usb-1-3"
+	for _, v := range list {
+		camera.AssignDeviceID(v)
+	}
+	if list[0].ID != list[1].ID {
+		t.Fatal("expected duplicate ids before dedupe")
+	}
+
+	// 未识别的序列号重复时改用端口区分
+	list.DedupeIDs()
+	if list[0].ID == list[1].ID || list[0].ID == list[0].LegacyID {
+		t.Errorf("unexpected ids %q, %q", list[0].ID, list[1].ID)
+	}
+
+	// 没有持久化标识属性时使用旧版ID
+	if list[2].ID != camera.LegacyDeviceID("/dev/video4", "Virtual") {
+		t.Errorf("unexpected fallback id %q", list[2].ID)
+	}
+
+	// 旧版ID仍可查询
+	dev, err := list.Get(list[1].LegacyID)
+	if err != nil || dev.SymbolicLink != "/dev/video2" {
+		t.Errorf("legacy lookup: %+v, %v", dev, err)
+	}
+}
+
+func TestDeviceIDWithTwin(t *testing.T) {
+	// 模拟枚举使用固定序列号的相机
+	enumerate := func(ports ...string) camera.DeviceList {
+		var list camera.DeviceList
+		for i, port := range ports {
+			dev := &camera.Device{
+				Name:         "USB Camera",
+				SymbolicLink: "/dev/video" + string(rune('0'+2*i)),
+				Identity:     camera.DeviceIdentity{VendorID: "1bcf", ProductID: "2284", Serial: "200901010001", PortPath: port},
+			}
+			camera.AssignDeviceID(dev)
+			list = append(list, dev)
+		}
+		list.DedupeIDs()
+		return list
+	}
+	// 单独接入时的ID
+	alone := enumerate("usb-1-2")
+
+	// 接入同型号相机后，原相机的ID不变
+	both := enumerate("usb-1-2", "usb-1-3")
+	if both[0].ID != alone[0].ID || both[1].ID == both[0].ID {
+		t.Errorf("id changed after attaching a twin: %q -> %q, twin %q", alone[0].ID, both[0].ID, both[1].ID)
+	}
+	if events := camera.DiffDeviceList(alone, both); len(events) != 1 || events[0].Type != camera.DeviceAdded {
+		t.Errorf("unexpected events %+v", events)
+	}
+
+	for serial, want := range map[string]bool{
+		"200901010001": true, "0001": true, "000000000001": true, "123456789": true,
+		"AAAA": true, "12": true, "ABC123": false, "K7F2Q9": false, "3A1B0C2D": false,
+	} {
+		if got := camera.IsGenericSerial(serial); got != want {
+			t.Errorf("IsGenericSerial(%q) = %v, want %v", serial, got, want)
+		}
+	}
+}
+
+func TestResolveDeviceMetadata(t *testing.T) {
+	root := t.TempDir()
+	writeFakeV4L2(t, root, "1-2", "video0", "ABC123", 0)
+	writeFakeV4L2(t, root, "1-2", "video1", "ABC123", 1)
+	usbDir := filepath.Join(root, "sys/devices/pci0000:00/0000:00:14.0/usb1/1-2")
+	for name, data := range map[string]string{"manufacturer": "Logitech\n", "product": "C270 HD WEBCAM\n", "bcdDevice": "0012\n"} {
+		if err := os.WriteFile(filepath.Join(usbDir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	driver := filepath.Join(root, "sys/bus/usb/drivers/uvcvideo")
+	if err := os.MkdirAll(driver, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(driver, filepath.Join(usbDir, "1-2:1.0/driver")); err != nil {
+		t.Fatal(err)
+	}
+
+	// 采集节点
+	meta := camera.ResolveDeviceMetadata(root, "/dev/video0")
+	want := camera.DeviceMetadata{
+		Driver:       "uvcvideo",
+		BusInfo:      "usb-0000:00:14.0-2",
+		Manufacturer: "Logitech",
+		Product:      "C270 HD WEBCAM",
+		Firmware:     "0.12",
+		Caps:         camera.CapVideoCapture | camera.CapStreaming,
+	}
+	if meta != want {
+		t.Errorf("unexpected metadata %+v", meta)
+	}
+
+	// UVC元数据节点
+	meta = camera.ResolveDeviceMetadata(root, "/dev/video1")
+	if !meta.Caps.IsMetadata() || meta.Caps.IsCapture() || meta.Caps.String() != "meta-capture|streaming" {
+		t.Errorf("unexpected caps %s", meta.Caps)
+	}
+
+	// Windows设备均为采集节点
+	meta = camera.ResolveDeviceMetadata(root, `\\?\usb#vid_046d&pid_0825&mi_00#6&2f6bb8b2&0&0000#{e5323777-f976-4f5b-9b55-b94699c46e44}\global`)
+	if !meta.Caps.IsCapture() {
+		t.Errorf("unexpected caps %s", meta.Caps)
+	}
+}
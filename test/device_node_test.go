@@ -0,0 +1,47 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestDeviceNodeTypeFromCaps(t *testing.T) {
+	cases := []struct {
+		caps camera.DeviceCaps
+		want camera.DeviceNodeType
+	}{
+		{camera.CapVideoCapture | camera.CapStreaming, camera.NodeCapture},
+		{camera.CapVideoCaptureMplane | camera.CapStreaming, camera.NodeCapture},
+		{camera.CapMetaCapture | camera.CapStreaming, camera.NodeMetadata},
+		{camera.CapVideoOutput | camera.CapStreaming, camera.NodeOutput},
+		{camera.CapVideoM2MMplane | camera.CapStreaming, camera.NodeM2M},
+		{camera.CapVideoCapture | camera.CapVideoOutput, camera.NodeM2M},
+		{0, camera.NodeCapture},
+	}
+	for _, c := range cases {
+		if got := camera.DeviceNodeTypeFromCaps(c.caps); got != c.want {
+			t.Errorf("DeviceNodeTypeFromCaps(%s) = %s, want %s", c.caps, got, c.want)
+		}
+	}
+}
+
+func TestFilterNodeTypes(t *testing.T) {
+	list := camera.DeviceList{
+		{ID: "video0", NodeType: camera.NodeCapture},
+		{ID: "video1", NodeType: camera.NodeMetadata},
+		{ID: "video2", NodeType: camera.NodeM2M},
+	}
+	if res := list.FilterNodeTypes(camera.NodeCapture); len(res) != 1 || res[0].ID != "video0" {
+		t.Errorf("capture only: %+v", res)
+	}
+	if res := list.FilterNodeTypes(camera.NodeCapture | camera.NodeMetadata); len(res) != 2 || res[1].ID != "video1" {
+		t.Errorf("capture and metadata: %+v", res)
+	}
+	if res := list.FilterNodeTypes(camera.NodeAll); len(res) != 3 {
+		t.Errorf("all: %+v", res)
+	}
+	if s := (camera.NodeCapture | camera.NodeMetadata).String(); s != "capture|metadata" {
+		t.Errorf("String() = %q", s)
+	}
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestDiffDeviceList(t *testing.T) {
+	old := camera.DeviceList{
+		{ID: "a", Name: "Front", SymbolicLink: "/dev/video0"},
+		{ID: "b", Name: "Back", SymbolicLink: "/dev/video2"},
+	}
+	new := camera.DeviceList{
+		{ID: "a", Name: "Front", SymbolicLink: "/dev/video4"},
+		{ID: "c", Name: "Side", SymbolicLink: "/dev/video2"},
+	}
+	events := camera.DiffDeviceList(old, new)
+	want := []struct {
+		typ camera.DeviceEventType
+		id  string
+	}{{camera.DeviceRemoved, "b"}, {camera.DeviceChanged, "a"}, {camera.DeviceAdded, "c"}}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, v := range want {
+		if events[i].Type != v.typ || events[i].Device.ID != v.id {
+			t.Errorf("event %d: %s %s, want %s %s", i, events[i].Type, events[i].Device.ID, v.typ, v.id)
+		}
+	}
+	if events := camera.DiffDeviceList(old, old.Clone()); len(events) != 0 {
+		t.Errorf("expected no events, got %+v", events)
+	}
+}
+
+func TestDeviceWatcherPoll(t *testing.T) {
+	var mutex sync.Mutex
+	list := camera.DeviceList{{ID: "a", Name: "Front"}}
+	watcher := &camera.DeviceWatcher{
+		List: func() (camera.DeviceList, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return list.Clone(), nil
+		},
+		Interval: 10 * time.Millisecond,
+		Poll:     true,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := watcher.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 接入新相机
+	mutex.Lock()
+	list = append(list, &camera.Device{ID: "b", Name: "Back"})
+	mutex.Unlock()
+	select {
+	case event := <-events:
+		if event.Type != camera.DeviceAdded || event.Device.ID != "b" {
+			t.Errorf("unexpected event %s %+v", event.Type, event.Device)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	// 取消后关闭通道
+	cancel()
+	for range events {
+	}
+}
+
+func TestDeviceWatcherRetry(t *testing.T) {
+	var mutex sync.Mutex
+	list := camera.DeviceList{{ID: "a", Name: "Front"}}
+	fail := false
+	notify := make(chan struct{}, 1)
+	watcher := &camera.DeviceWatcher{
+		List: func() (camera.DeviceList, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if fail {
+				fail = false
+				return nil, camera.ErrEnumDeviceFailed
+			}
+			return list.Clone(), nil
+		},
+		Interval: 10 * time.Millisecond,
+		Notify:   notify,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := watcher.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 事件触发后的首次枚举失败，按轮询间隔重试后仍然上报
+	mutex.Lock()
+	list = append(list, &camera.Device{ID: "b", Name: "Back"})
+	fail = true
+	mutex.Unlock()
+	notify <- struct{}{}
+	select {
+	case event := <-events:
+		if event.Type != camera.DeviceAdded || event.Device.ID != "b" {
+			t.Errorf("unexpected event %s %+v", event.Type, event.Device)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for retried event")
+	}
+}
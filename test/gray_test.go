@@ -0,0 +1,58 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestDecodeGray16(t *testing.T) {
+	// 4个像素：0x000, 0x155, 0x2AA, 0x3FF
+	want := []uint16{0x0000, 0x5555, 0xaaaa, 0xffff}
+	cases := map[camera.Fourcc][]byte{
+		camera.FOURCC_Y10:      {0x00, 0x00, 0x55, 0x01, 0xaa, 0x02, 0xff, 0x03},
+		camera.FOURCC_Y10P:     {0x00, 0x55, 0xaa, 0xff, 0b11_10_01_00},
+		camera.FOURCC_Y10BPACK: {0x00, 0x15, 0x5a, 0xab, 0xff},
+	}
+	for format, data := range cases {
+		img, err := camera.DecodeGray16(data, &camera.DeviceConfig{Width: 4, Height: 1, Format: format})
+		if err != nil {
+			t.Fatal(format, err)
+		}
+		for x, v := range want {
+			if got := img.Gray16At(x, 0).Y; got != v {
+				t.Errorf("%s: pixel %d = %#04x, want %#04x", format, x, got, v)
+			}
+		}
+	}
+
+	// 长度不足
+	_, err := camera.DecodeGray16(make([]byte, 7), &camera.DeviceConfig{Width: 4, Height: 1, Format: camera.FOURCC_Y16})
+	if !errors.Is(err, camera.ErrFrameSizeMismatch) {
+		t.Errorf("short frame error = %v", err)
+	}
+	// 非高位深格式
+	_, err = camera.DecodeGray16(make([]byte, 8), &camera.DeviceConfig{Width: 4, Height: 1, Format: camera.FOURCC_GREY})
+	if !errors.Is(err, camera.ErrUnsupportedFrameFormat) {
+		t.Errorf("unsupported format error = %v", err)
+	}
+}
+
+func TestGray16ToGray(t *testing.T) {
+	img, err := camera.DecodeGray16([]byte{0x00, 0x01, 0x00, 0x02, 0x00, 0x03}, &camera.DeviceConfig{Width: 3, Height: 1, Format: camera.FOURCC_Y16})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 自动窗宽窗位应拉伸到全范围
+	gray := camera.Gray16ToGray(img, camera.AutoWindowLevel(img))
+	if gray.Pix[0] != 0 || gray.Pix[2] != 0xff {
+		t.Errorf("auto window/level = %v", gray.Pix)
+	}
+	// 全范围映射时高字节即为8位灰度
+	gray = camera.Gray16ToGray(img, camera.WindowLevel{})
+	if gray.Pix[2] != 0x03 {
+		t.Errorf("full range = %v", gray.Pix)
+	}
+}
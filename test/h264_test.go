@@ -0,0 +1,142 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+// 测试用码流位写入器
+type bitWriter struct {
+	data []byte
+	n    int
+}
+
+func (w *bitWriter) u(n int, v uint32) {
+	for i := n - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		w.data[len(w.data)-1] |= byte(v>>i&1) << (7 - w.n%8)
+		w.n++
+	}
+}
+
+func (w *bitWriter) ue(v uint32) {
+	n := 0
+	for (v+1)>>n > 1 {
+		n++
+	}
+	w.u(n, 0)
+	w.u(n+1, v+1)
+}
+
+// 写入RBSP尾部并添加防竞争字节
+func (w *bitWriter) nal(header byte) []byte {
+	w.u(1, 1)
+	res := []byte{header}
+	zeros := 0
+	for _, b := range w.data {
+		if zeros >= 2 && b <= 3 {
+			res = append(res, 0x03)
+			zeros = 0
+		}
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+		res = append(res, b)
+	}
+	return res
+}
+
+// 构造High档次1920x1080@30的SPS（含裁剪与VUI帧率）
+func testH264SPS() []byte {
+	w := &bitWriter{}
+	w.u(8, 100) // profile_idc
+	w.u(8, 0)   // constraint flags
+	w.u(8, 40)  // level_idc
+	w.ue(0)     // seq_parameter_set_id
+	w.ue(1)     // chroma_format_idc
+	w.ue(0)     // bit_depth_luma_minus8
+	w.ue(0)     // bit_depth_chroma_minus8
+	w.u(1, 0)   // qpprime_y_zero_transform_bypass_flag
+	w.u(1, 0)   // seq_scaling_matrix_present_flag
+	w.ue(0)     // log2_max_frame_num_minus4
+	w.ue(0)     // pic_order_cnt_type
+	w.ue(0)     // log2_max_pic_order_cnt_lsb_minus4
+	w.ue(4)     // max_num_ref_frames
+	w.u(1, 0)   // gaps_in_frame_num_value_allowed_flag
+	w.ue(119)   // pic_width_in_mbs_minus1
+	w.ue(67)    // pic_height_in_map_units_minus1
+	w.u(1, 1)   // frame_mbs_only_flag
+	w.u(1, 1)   // direct_8x8_inference_flag
+	w.u(1, 1)   // frame_cropping_flag
+	w.ue(0)
+	w.ue(0)
+	w.ue(0)
+	w.ue(4)
+	w.u(1, 1) // vui_parameters_present_flag
+	w.u(1, 0) // aspect_ratio_info_present_flag
+	w.u(1, 0) // overscan_info_present_flag
+	w.u(1, 0) // video_signal_type_present_flag
+	w.u(1, 0) // chroma_loc_info_present_flag
+	w.u(1, 1) // timing_info_present_flag
+	w.u(32, 1001)
+	w.u(32, 60000)
+	w.u(1, 1)
+	return w.nal(0x67)
+}
+
+func TestParseH264Frame(t *testing.T) {
+	pw := &bitWriter{}
+	pw.ue(0)   // pic_parameter_set_id
+	pw.ue(0)   // seq_parameter_set_id
+	pw.u(1, 1) // entropy_coding_mode_flag
+	pps := pw.nal(0x68)
+	idr := []byte{0x65, 0x88, 0x84, 0x00, 0x00, 0x03, 0x01}
+	data := bytes.Join([][]byte{nil, {0x09, 0xf0}, testH264SPS(), pps, idr}, []byte{0, 0, 0, 1})
+
+	info, err := camera.ParseH264Frame(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.NALUnits) != 4 || !info.Keyframe {
+		t.Fatalf("got %d nal units, keyframe %v", len(info.NALUnits), info.Keyframe)
+	}
+	if !bytes.Equal(info.NALUnits[3].Data, idr) {
+		t.Errorf("idr nal unit %x, want %x", info.NALUnits[3].Data, idr)
+	}
+	sps := info.SPS
+	if sps == nil || sps.Width != 1920 || sps.Height != 1080 || sps.Profile() != "High" || sps.Level() != "4.0" {
+		t.Fatalf("unexpected sps %+v", sps)
+	}
+	if fps := sps.FrameRate(); fps < 29.97 || fps > 29.98 {
+		t.Errorf("frame rate %v", fps)
+	}
+	if cfg := sps.Config(); cfg != camera.NewDeviceConfigWithInterval(1920, 1080, camera.NewFrameInterval(1001, 30000), camera.FOURCC_H264) {
+		t.Errorf("config %+v", cfg)
+	}
+	if info.PPS == nil || !info.PPS.EntropyCodingCABAC {
+		t.Errorf("unexpected pps %+v", info.PPS)
+	}
+
+	// P帧
+	info, err = camera.ParseH264Frame([]byte{0, 0, 1, 0x41, 0x9a, 0x00})
+	if err != nil || info.Keyframe || info.SPS != nil {
+		t.Errorf("p frame parsed as %+v, %v", info, err)
+	}
+}
+
+func TestParseH264Failed(t *testing.T) {
+	if _, err := camera.ParseH264Frame([]byte{1, 2, 3}); !errors.Is(err, camera.ErrParseBitstreamFailed) {
+		t.Errorf("expected ErrParseBitstreamFailed, got %v", err)
+	}
+	sps := testH264SPS()
+	if _, err := camera.ParseH264SPS(sps[:6]); !errors.Is(err, camera.ErrParseBitstreamFailed) {
+		t.Errorf("expected ErrParseBitstreamFailed, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestCheckMJPEG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := camera.EncodeJPEG(&buf, gradientI420(64, 48), &camera.JpegEncodeOptions{RestartInterval: 2}); err != nil {
+		t.Fatal(err)
+	}
+	good := buf.Bytes()
+
+	// 有效帧及0x00填充
+	if err := camera.CheckMJPEG(good); err != nil {
+		t.Fatal(err)
+	}
+	if err := camera.CheckMJPEG(append(append([]byte(nil), good...), 0, 0, 0)); err != nil {
+		t.Errorf("zero padding should be accepted: %v", err)
+	}
+	// 缺少DHT段的UVC帧同样有效
+	if err := camera.CheckMJPEG(stripDHT(good)); err != nil {
+		t.Errorf("frame without DHT should be accepted: %v", err)
+	}
+
+	// 重启标记跳号
+	skipped := append([]byte(nil), good...)
+	if i := bytes.Index(skipped, []byte{0xff, 0xd1}); i > 0 {
+		skipped[i+1] = 0xd2
+	}
+
+	cases := map[string][]byte{
+		"empty":       nil,
+		"no soi":      good[2:],
+		"header only": good[:20],
+		"truncated":   good[:len(good)*2/3],
+		"no eoi":      good[:len(good)-2],
+		"garbage":     append(append([]byte(nil), good...), 0x12, 0x34),
+		"rst order":   skipped,
+		"second soi":  append(append([]byte(nil), good[:len(good)-2]...), good...),
+	}
+	for name, data := range cases {
+		if err := camera.CheckMJPEG(data); !errors.Is(err, camera.ErrFrameCorrupted) {
+			t.Errorf("%s: expected ErrFrameCorrupted, got %v", name, err)
+		}
+	}
+
+	// 非MJPEG格式直接通过
+	if err := camera.CheckFrame(good[:10], &camera.DeviceConfig{Format: camera.FOURCC_YUYV}); err != nil {
+		t.Error(err)
+	}
+	if err := camera.CheckFrame(good[:10], &camera.DeviceConfig{Format: camera.FOURCC_MJPEG}); !errors.Is(err, camera.ErrFrameCorrupted) {
+		t.Errorf("expected ErrFrameCorrupted, got %v", err)
+	}
+}
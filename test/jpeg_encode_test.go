@@ -0,0 +1,136 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+// 生成渐变I420帧
+func gradientI420(w, h int) *camera.Frame {
+	cw, ch := (w+1)/2, (h+1)/2
+	data := make([]byte, w*h+2*cw*ch)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			data[y*w+x] = uint8(16 + (x+y)*219/(w+h))
+		}
+	}
+	for i := 0; i < cw*ch; i++ {
+		data[w*h+i], data[w*h+cw*ch+i] = 100, 160
+	}
+	return &camera.Frame{Data: data, Config: camera.DeviceConfig{Width: uint32(w), Height: uint32(h), Format: camera.FOURCC_YUV420}}
+}
+
+// 以有限范围比较解码结果与源亮度
+func checkEncodedLuma(t *testing.T, name string, data []byte, luma []byte, w, h int) *image.YCbCr {
+	t.Helper()
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	ycc, ok := img.(*image.YCbCr)
+	if !ok {
+		t.Fatalf("%s: unexpected image type %T", name, img)
+	}
+	if ycc.Rect.Dx() != w || ycc.Rect.Dy() != h {
+		t.Fatalf("%s: size %v, want %dx%d", name, ycc.Rect, w, h)
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			want := (int(luma[y*w+x]) - 16) * 255 / 219
+			if d := int(ycc.Y[ycc.YOffset(x, y)]) - want; d < -6 || d > 6 {
+				t.Fatalf("%s: luma at (%d,%d) = %d, want %d", name, x, y, ycc.Y[ycc.YOffset(x, y)], want)
+			}
+		}
+	}
+	return ycc
+}
+
+func TestEncodeJPEG(t *testing.T) {
+	i420 := gradientI420(37, 21)
+	w, h := 37, 21
+	ySize, cSize := w*h, 19*11
+
+	// 由I420构造NV12与YUYV
+	nv12 := &camera.Frame{Data: append([]byte(nil), i420.Data[:ySize]...), Config: i420.Config}
+	nv12.Config.Format = camera.FOURCC_NV12
+	for i := 0; i < cSize; i++ {
+		nv12.Data = append(nv12.Data, i420.Data[ySize+i], i420.Data[ySize+cSize+i])
+	}
+	yuyv := &camera.Frame{Config: i420.Config}
+	yuyv.Config.Format = camera.FOURCC_YUYV
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x += 2 {
+			x1 := x + 1
+			if x1 >= w {
+				x1 = x
+			}
+			yuyv.Data = append(yuyv.Data, i420.Data[y*w+x], 100, i420.Data[y*w+x1], 160)
+		}
+	}
+
+	for _, frame := range []*camera.Frame{i420, nv12, yuyv} {
+		var buf bytes.Buffer
+		if err := camera.EncodeJPEG(&buf, frame, &camera.JpegEncodeOptions{Quality: 95}); err != nil {
+			t.Fatal(frame.Config.Format, err)
+		}
+		ycc := checkEncodedLuma(t, frame.Config.Format.String(), buf.Bytes(), i420.Data, w, h)
+		wantRatio := image.YCbCrSubsampleRatio420
+		if frame.Config.Format == camera.FOURCC_YUYV {
+			wantRatio = image.YCbCrSubsampleRatio422
+		}
+		if ycc.SubsampleRatio != wantRatio {
+			t.Errorf("%s: subsample ratio %v, want %v", frame.Config.Format, ycc.SubsampleRatio, wantRatio)
+		}
+		// 色度由有限范围100/160映射到全范围
+		if cb, cr := ycc.Cb[0], ycc.Cr[0]; cb < 94 || cb > 98 || cr < 162 || cr > 166 {
+			t.Errorf("%s: chroma (%d, %d)", frame.Config.Format, cb, cr)
+		}
+	}
+}
+
+func TestEncodeJPEGRestartInterval(t *testing.T) {
+	frame := gradientI420(64, 48)
+	var plain, restart bytes.Buffer
+	if err := camera.EncodeJPEG(&plain, frame, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := camera.EncodeJPEG(&restart, frame, &camera.JpegEncodeOptions{RestartInterval: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(restart.Bytes(), []byte{0xff, 0xdd, 0x00, 0x04, 0x00, 0x02}) {
+		t.Error("missing DRI segment")
+	}
+	for m := byte(0xd0); m <= 0xd4; m++ {
+		if !bytes.Contains(restart.Bytes(), []byte{0xff, m}) {
+			t.Errorf("missing RST%d marker", m-0xd0)
+		}
+	}
+
+	// 插入重启标记不影响解码结果
+	a := checkEncodedLuma(t, "plain", plain.Bytes(), frame.Data, 64, 48)
+	b := checkEncodedLuma(t, "restart", restart.Bytes(), frame.Data, 64, 48)
+	if !bytes.Equal(a.Y, b.Y) || !bytes.Equal(a.Cb, b.Cb) {
+		t.Error("restart interval changed decoded image")
+	}
+}
+
+func TestEncodeJPEGInvalid(t *testing.T) {
+	frame := gradientI420(16, 16)
+	var buf bytes.Buffer
+	if err := camera.EncodeJPEG(&buf, frame, &camera.JpegEncodeOptions{Quality: 101}); !errors.Is(err, camera.ErrInvalidParam) {
+		t.Errorf("expected ErrInvalidParam, got %v", err)
+	}
+	rgb := &camera.Frame{Data: make([]byte, 16*16*3), Config: camera.DeviceConfig{Width: 16, Height: 16, Format: camera.FOURCC_RGB24}}
+	if err := camera.EncodeJPEG(&buf, rgb, nil); !errors.Is(err, camera.ErrUnsupportedFrameFormat) {
+		t.Errorf("expected ErrUnsupportedFrameFormat, got %v", err)
+	}
+	frame.Data = frame.Data[:100]
+	if err := camera.EncodeJPEG(&buf, frame, nil); !errors.Is(err, camera.ErrFrameSizeMismatch) {
+		t.Errorf("expected ErrFrameSizeMismatch, got %v", err)
+	}
+}
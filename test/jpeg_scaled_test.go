@@ -0,0 +1,167 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+// 以块平均的方式缩小图像平面，作为缩放解码的参考值
+func boxAverage(plane []byte, stride, w, h, scale, x, y int) int {
+	sum, n := 0, 0
+	for dy := 0; dy < scale && y*scale+dy < h; dy++ {
+		for dx := 0; dx < scale && x*scale+dx < w; dx++ {
+			sum += int(plane[(y*scale+dy)*stride+x*scale+dx])
+			n++
+		}
+	}
+	return sum / n
+}
+
+// 比较缩放解码结果与完整解码后缩小的结果
+func checkScaledDecode(t *testing.T, name string, data []byte) {
+	t.Helper()
+	full, err := camera.DecodeMJPEG(data)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	w, h := full.Rect.Dx(), full.Rect.Dy()
+	for _, scale := range []camera.JpegScale{camera.JpegScaleHalf, camera.JpegScaleQuarter, camera.JpegScaleEighth} {
+		img, err := camera.DecodeMJPEGScaled(data, scale)
+		if err != nil {
+			t.Fatalf("%s 1/%d: %v", name, scale, err)
+		}
+		s := int(scale)
+		if img.Rect.Dx() != (w+s-1)/s || img.Rect.Dy() != (h+s-1)/s {
+			t.Fatalf("%s 1/%d: size %v", name, scale, img.Rect)
+		}
+		if img.SubsampleRatio != full.SubsampleRatio {
+			t.Errorf("%s 1/%d: subsample ratio %v, want %v", name, scale, img.SubsampleRatio, full.SubsampleRatio)
+		}
+		// 仅比较完整块内的像素，避免边缘填充的影响
+		for y := 0; y < h/8*8/s; y++ {
+			for x := 0; x < w/8*8/s; x++ {
+				want := boxAverage(full.Y, full.YStride, w, h, s, x, y)
+				if d := int(img.Y[img.YOffset(x, y)]) - want; d < -12 || d > 12 {
+					t.Fatalf("%s 1/%d: luma at (%d,%d) = %d, want about %d", name, scale, x, y, img.Y[img.YOffset(x, y)], want)
+				}
+			}
+		}
+		if d := int(img.Cb[0]) - int(full.Cb[0]); d < -12 || d > 12 {
+			t.Errorf("%s 1/%d: chroma %d, want about %d", name, scale, img.Cb[0], full.Cb[0])
+		}
+	}
+}
+
+func TestDecodeMJPEGScaled(t *testing.T) {
+	// 标准库编码的4:2:0图像（去除DHT段）
+	src := image.NewRGBA(image.Rect(0, 0, 100, 60))
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 100; x++ {
+			px := src.Pix[src.PixOffset(x, y):]
+			px[0], px[1], px[2], px[3] = uint8(x*2), uint8(y*4), 90, 0xff
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	}
+	checkScaledDecode(t, "stdlib", stripDHT(buf.Bytes()))
+
+	// 4:2:2编码且带重启间隔
+	buf.Reset()
+	frame := gradientI420(64, 48)
+	yuyv := &camera.Frame{Config: frame.Config}
+	yuyv.Config.Format = camera.FOURCC_YUYV
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x += 2 {
+			yuyv.Data = append(yuyv.Data, frame.Data[y*64+x], 110, frame.Data[y*64+x+1], 150)
+		}
+	}
+	if err := camera.EncodeJPEG(&buf, yuyv, &camera.JpegEncodeOptions{Quality: 90, RestartInterval: 3}); err != nil {
+		t.Fatal(err)
+	}
+	checkScaledDecode(t, "yuyv", buf.Bytes())
+
+	// 灰度图像
+	buf.Reset()
+	gray := image.NewGray(image.Rect(0, 0, 40, 24))
+	for y := 0; y < 24; y++ {
+		for x := 0; x < 40; x++ {
+			gray.Pix[y*40+x] = uint8((x + y) * 4)
+		}
+	}
+	if err := jpeg.Encode(&buf, gray, nil); err != nil {
+		t.Fatal(err)
+	}
+	checkScaledDecode(t, "gray", buf.Bytes())
+}
+
+func TestDecodeMJPEGScaledFailed(t *testing.T) {
+	var buf bytes.Buffer
+	if err := camera.EncodeJPEG(&buf, gradientI420(32, 32), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := camera.DecodeMJPEGScaled(buf.Bytes(), 3); !errors.Is(err, camera.ErrInvalidParam) {
+		t.Errorf("expected ErrInvalidParam, got %v", err)
+	}
+	if _, err := camera.DecodeMJPEGScaled(buf.Bytes()[:20], camera.JpegScaleHalf); !errors.Is(err, camera.ErrDecodeJpegImageFailed) {
+		t.Errorf("expected ErrDecodeJpegImageFailed, got %v", err)
+	}
+}
+
+func TestDecodeMJPEGScaledMarkers(t *testing.T) {
+	var buf bytes.Buffer
+	if err := camera.EncodeJPEG(&buf, gradientI420(32, 32), nil); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	want, err := camera.DecodeMJPEGScaled(data, camera.JpegScaleHalf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// SOS之前的独立标记（TEM、RSTn）没有长度字段，应被跳过
+	for _, marker := range []byte{0x01, 0xd0, 0xd7} {
+		withMarker := append([]byte{0xff, 0xd8, 0xff, marker}, data[2:]...)
+		img, err := camera.DecodeMJPEGScaled(withMarker, camera.JpegScaleHalf)
+		if err != nil {
+			t.Fatalf("marker %02x: %v", marker, err)
+		}
+		if !bytes.Equal(img.Y, want.Y) {
+			t.Errorf("marker %02x: unexpected luma", marker)
+		}
+	}
+
+	// 扫描引用未定义的Huffman表
+	sos := bytes.Index(data, []byte{0xff, 0xda})
+	if sos < 0 {
+		t.Fatal("missing SOS")
+	}
+	corrupt := append([]byte(nil), data...)
+	corrupt[sos+6] = 0x23
+	if _, err := camera.DecodeMJPEGScaled(corrupt, camera.JpegScaleHalf); !errors.Is(err, camera.ErrDecodeJpegImageFailed) {
+		t.Errorf("expected ErrDecodeJpegImageFailed, got %v", err)
+	}
+}
+
+func TestDecodeMJPEGScaledInvalidDHT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := camera.EncodeJPEG(&buf, gradientI420(16, 16), nil); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// 码长1最多只能有2个码字，此处声明了3个
+	dht := []byte{0xff, 0xc4, 0x00, 2 + 17 + 3, 0x00, 3}
+	dht = append(dht, make([]byte, 15)...)
+	dht = append(dht, 0, 1, 2)
+	corrupt := append(append([]byte{0xff, 0xd8}, dht...), data[2:]...)
+	if _, err := camera.DecodeMJPEGScaled(corrupt, camera.JpegScaleHalf); !errors.Is(err, camera.ErrDecodeJpegImageFailed) {
+		t.Errorf("expected ErrDecodeJpegImageFailed, got %v", err)
+	}
+}
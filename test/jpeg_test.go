@@ -0,0 +1,88 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+// 编码测试用JPEG图像
+func encodeTestJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewYCbCr(image.Rect(0, 0, w, h), image.YCbCrSubsampleRatio422)
+	for i := range img.Y {
+		img.Y[i] = uint8(i * 7)
+	}
+	for i := range img.Cb {
+		img.Cb[i], img.Cr[i] = uint8(i*3), uint8(255-i*5)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 80}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// 移除JPEG数据中的DHT段，模拟UVC摄像头输出的MJPEG帧
+func stripDHT(data []byte) []byte {
+	res := append([]byte(nil), data[:2]...)
+	for pos := 2; pos+4 <= len(data); {
+		marker := data[pos+1]
+		if marker == 0xda {
+			return append(res, data[pos:]...)
+		}
+		size := int(data[pos+2])<<8 | int(data[pos+3])
+		if marker != 0xc4 {
+			res = append(res, data[pos:pos+2+size]...)
+		}
+		pos += 2 + size
+	}
+	return res
+}
+
+func TestDecodeMJPEG(t *testing.T) {
+	data := encodeTestJPEG(t, 32, 16)
+	want, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 缺少DHT段时标准库无法解码
+	noDHT := stripDHT(data)
+	if _, err := jpeg.Decode(bytes.NewReader(noDHT)); err == nil {
+		t.Fatal("expected image/jpeg to reject frame without DHT")
+	}
+
+	// 注入标准表后与原图一致
+	got, err := camera.DecodeMJPEG(noDHT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantYCbCr := want.(*image.YCbCr)
+	if !bytes.Equal(got.Y, wantYCbCr.Y) || !bytes.Equal(got.Cb, wantYCbCr.Cb) || !bytes.Equal(got.Cr, wantYCbCr.Cr) {
+		t.Error("decoded planes differ from original")
+	}
+
+	// 已包含DHT段时原样返回
+	if res, err := camera.InjectHuffmanTables(data); err != nil || &res[0] != &data[0] {
+		t.Errorf("frame with DHT should be returned unchanged: %v", err)
+	}
+}
+
+func TestDecodeMJPEGFailed(t *testing.T) {
+	data := encodeTestJPEG(t, 16, 16)
+	cases := map[string][]byte{
+		"empty":     nil,
+		"no soi":    data[2:],
+		"truncated": data[:len(data)/2],
+	}
+	for name, c := range cases {
+		if _, err := camera.DecodeMJPEG(c); !errors.Is(err, camera.ErrDecodeJpegImageFailed) {
+			t.Errorf("%s: expected ErrDecodeJpegImageFailed, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestOpenWithFallback(t *testing.T) {
+	candidates := testConfigList().Rank(&camera.ConfigSelector{Width: 1920, Height: 1080, FPS: 30, Formats: []camera.Fourcc{camera.FOURCC_MJPEG}})
+
+	// 前两个候选配置无法出流，回退到第三个
+	var tried []camera.DeviceConfig
+	res, err := camera.OpenWithFallback(candidates, func(cfg camera.DeviceConfig) error {
+		tried = append(tried, cfg)
+		if len(tried) < 3 {
+			return camera.ErrFrameTimeout
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Attempts) != 3 || res.Config == nil || !res.Config.Eq(candidates[2].Config) || !res.Config.Eq(&tried[2]) {
+		t.Fatalf("unexpected result %s", res)
+	}
+	if !errors.Is(res.Attempts[0].Err, camera.ErrFrameTimeout) || res.Attempts[2].Err != nil {
+		t.Errorf("unexpected attempts %s", res)
+	}
+	if s := res.String(); !strings.HasPrefix(s, "opened "+candidates[2].Config.String()) {
+		t.Errorf("unexpected explanation %q", s)
+	}
+
+	// 全部失败
+	res, err = camera.OpenWithFallback(candidates, func(cfg camera.DeviceConfig) error { return camera.ErrFrameTimeout })
+	if !errors.Is(err, camera.ErrDeviceOpenFailed) || !errors.Is(err, camera.ErrFrameTimeout) {
+		t.Errorf("expected ErrDeviceOpenFailed and ErrFrameTimeout, got %v", err)
+	}
+	if res.Config != nil || len(res.Attempts) != len(candidates) {
+		t.Errorf("unexpected result %s", res)
+	}
+
+	// 没有候选配置
+	if _, err := camera.OpenWithFallback(nil, nil); err != camera.ErrDeviceMediaConfigNotFound {
+		t.Errorf("expected ErrDeviceMediaConfigNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"image/color"
+	"runtime"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestDecodeRGBA(t *testing.T) {
+	cases := []struct {
+		format camera.Fourcc
+		data   []byte
+		want   color.RGBA
+	}{
+		{camera.FOURCC_RGB332, []byte{0b111_000_11}, color.RGBA{0xff, 0x00, 0xff, 0xff}},
+		{camera.FOURCC_RGB565, []byte{0x1f, 0xf8}, color.RGBA{0xff, 0x00, 0xff, 0xff}},
+		{camera.FOURCC_RGB565X, []byte{0xf8, 0x1f}, color.RGBA{0xff, 0x00, 0xff, 0xff}},
+		{camera.FOURCC_ARGB555, []byte{0x00, 0x7c}, color.RGBA{0x00, 0x00, 0x00, 0x00}},
+		{camera.FOURCC_ARGB444, []byte{0x00, 0x8f}, color.RGBA{0x88, 0x00, 0x00, 0x88}},
+		{camera.FOURCC_XRGB444, []byte{0x00, 0x0f}, color.RGBA{0xff, 0x00, 0x00, 0xff}},
+		{camera.FOURCC_BGR24, []byte{0x01, 0x02, 0x03}, color.RGBA{0x03, 0x02, 0x01, 0xff}},
+		{camera.FOURCC_RGB24, []byte{0x01, 0x02, 0x03}, color.RGBA{0x01, 0x02, 0x03, 0xff}},
+		{camera.FOURCC_ABGR32, []byte{0xff, 0x00, 0x00, 0x80}, color.RGBA{0x00, 0x00, 0x80, 0x80}},
+		{camera.FOURCC_XRGB32, []byte{0x00, 0x01, 0x02, 0x03}, color.RGBA{0x01, 0x02, 0x03, 0xff}},
+	}
+	for _, c := range cases {
+		img, err := camera.DecodeRGBAWithOrientation(c.data, &camera.DeviceConfig{Width: 1, Height: 1, Format: c.format}, camera.RGBOrientationTopDown)
+		if err != nil {
+			t.Fatal(c.format, err)
+		}
+		if got := img.RGBAAt(0, 0); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.format, got, c.want)
+		}
+	}
+}
+
+func TestDecodeRGBABottomUp(t *testing.T) {
+	// 2x2 BGR24，行尾填充到4字节对齐
+	data := []byte{
+		0, 0, 1, 0, 0, 2, 0, 0,
+		0, 0, 3, 0, 0, 4, 0, 0,
+	}
+	img, err := camera.DecodeRGBAWithOrientation(data, &camera.DeviceConfig{Width: 2, Height: 2, Format: camera.FOURCC_BGR24}, camera.RGBOrientationBottomUp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.RGBAAt(0, 0).R != 3 || img.RGBAAt(1, 1).R != 2 {
+		t.Errorf("bottom-up rows not flipped: %v", img.Pix)
+	}
+}
+
+func TestIsBottomUp(t *testing.T) {
+	for _, format := range []camera.Fourcc{camera.FOURCC_RGB24, camera.FOURCC_BGR24} {
+		if got, want := camera.IsBottomUp(format), runtime.GOOS == "windows"; got != want {
+			t.Errorf("%s: got %v, want %v", format, got, want)
+		}
+	}
+	for _, format := range []camera.Fourcc{camera.FOURCC_RGB565, camera.FOURCC_XRGB32, camera.FOURCC_ABGR32} {
+		if camera.IsBottomUp(format) {
+			t.Errorf("%s: expected top-down", format)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestDecodeStereo(t *testing.T) {
+	// Y8I：左右交织
+	frame, err := camera.DecodeStereo([]byte{1, 2, 3, 4}, &camera.DeviceConfig{Width: 2, Height: 1, Format: camera.FOURCC_Y8I})
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, right := frame.Left.(*image.Gray), frame.Right.(*image.Gray)
+	if left.Pix[0] != 1 || left.Pix[1] != 3 || right.Pix[0] != 2 || right.Pix[1] != 4 {
+		t.Errorf("y8i split = %v %v", left.Pix, right.Pix)
+	}
+
+	// Y12I：左目0xFFF，右目0x000
+	left16, right16, err := camera.SplitY12I([]byte{0xff, 0x0f, 0x00}, &camera.DeviceConfig{Width: 1, Height: 1, Format: camera.FOURCC_Y12I})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left16.Gray16At(0, 0).Y != 0xffff || right16.Gray16At(0, 0).Y != 0 {
+		t.Errorf("y12i split = %v %v", left16.Pix, right16.Pix)
+	}
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bearki/go-becam/camera"
+)
+
+func TestStreamCacheH264(t *testing.T) {
+	cfg := camera.NewDeviceConfig(1920, 1080, 30, camera.FOURCC_H264)
+	sps, pps := testH264SPS(), []byte{0x68, 0xce, 0x3c, 0x80}
+	annexB := func(nals ...[]byte) []byte {
+		return bytes.Join(append([][]byte{nil}, nals...), []byte{0, 0, 0, 1})
+	}
+	cache := camera.NewStreamCache(3)
+
+	// GOP开始前的P帧被丢弃，参数集单独成帧时同样会被缓存
+	push := func(data []byte, wantKey bool) {
+		t.Helper()
+		key, err := cache.Push(&camera.Frame{Data: data, Config: cfg})
+		if err != nil || key != wantKey {
+			t.Fatalf("push %x: keyframe %v, %v", data, key, err)
+		}
+	}
+	push(annexB([]byte{0x41, 0x9a, 0x01}), false)
+	push(annexB(sps, pps), false)
+	if gop := cache.GOP(); gop != nil {
+		t.Fatalf("expected empty gop, got %d frames", len(gop))
+	}
+	idr := annexB([]byte{0x65, 0x88, 0x84})
+	push(idr, true)
+	push(annexB([]byte{0x41, 0x9a, 0x02}), false)
+
+	gop := cache.GOP()
+	if len(gop) != 2 {
+		t.Fatalf("expected 2 frames, got %d", len(gop))
+	}
+	if want := append(annexB(sps, pps), idr...); !bytes.Equal(gop[0].Data, want) {
+		t.Errorf("keyframe %x, want %x", gop[0].Data, want)
+	}
+	if !bytes.Equal(cache.ParameterSets(), annexB(sps, pps)) {
+		t.Errorf("parameter sets %x", cache.ParameterSets())
+	}
+
+	// 超过最大帧数后清空GOP
+	push(annexB([]byte{0x41, 0x9a, 0x03}), false)
+	push(annexB([]byte{0x41, 0x9a, 0x04}), false)
+	if gop := cache.GOP(); gop != nil {
+		t.Errorf("expected gop to be dropped, got %d frames", len(gop))
+	}
+
+	// 配置变化时清空参数集
+	cfg.Width = 1280
+	push(idr, true)
+	if gop := cache.GOP(); len(gop) != 1 || !bytes.Equal(gop[0].Data, idr) || cache.ParameterSets() != nil {
+		t.Errorf("unexpected gop after config change %+v", gop)
+	}
+}
+
+func TestStreamCacheHEVCAndVP8(t *testing.T) {
+	cache := camera.NewStreamCache(0)
+	vps, sps, pps := []byte{0x40, 0x01, 0x0c}, testHEVCSPS(), []byte{0x44, 0x01, 0xc1}
+	key := bytes.Join([][]byte{nil, vps, sps, pps, {0x26, 0x01, 0xaf}}, []byte{0, 0, 0, 1})
+	cfg := camera.NewDeviceConfig(1280, 720, 30, camera.FOURCC_HEVC)
+	if ok, err := cache.Push(&camera.Frame{Data: key, Config: cfg}); !ok || err != nil {
+		t.Fatalf("hevc keyframe: %v, %v", ok, err)
+	}
+	if gop := cache.GOP(); len(gop) != 1 || !bytes.Equal(gop[0].Data, key) {
+		t.Errorf("hevc keyframe with parameter sets should be kept as is")
+	}
+
+	// VP8关键帧
+	vp8 := camera.NewDeviceConfig(640, 480, 30, camera.FOURCC_VP8)
+	inter := &camera.Frame{Data: []byte{0x31, 0x02, 0x00, 0x11, 0x22}, Config: vp8}
+	keyframe := &camera.Frame{Data: []byte{0x50, 0x42, 0x00, 0x9d, 0x01, 0x2a, 0x80, 0x02, 0xe0, 0x01}, Config: vp8}
+	for _, frame := range []*camera.Frame{inter, keyframe, inter} {
+		if _, err := cache.Push(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gop := cache.GOP(); len(gop) != 2 || !bytes.Equal(gop[0].Data, keyframe.Data) {
+		t.Errorf("unexpected vp8 gop %+v", gop)
+	}
+
+	// 非压缩格式
+	_, err := cache.Push(&camera.Frame{Data: []byte{0}, Config: camera.NewDeviceConfig(1, 1, 30, camera.FOURCC_MJPEG)})
+	if !errors.Is(err, camera.ErrUnsupportedFrameFormat) {
+		t.Errorf("expected ErrUnsupportedFrameFormat, got %v", err)
+	}
+}